@@ -0,0 +1,91 @@
+package main
+
+import (
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+// advancedAdd 高级添加栏：在输入框下方填写截止时间、优先级与标签，无需使用行内标记。
+// toggle 展开或收起 row，状态保存在偏好设置中
+type advancedAdd struct {
+	row      *fyne.Container
+	toggle   *widget.Button
+	due      *widget.Entry
+	priority *widget.Select
+	tags     *widget.Entry
+	p        fyne.Preferences
+}
+
+// newAdvancedAdd 创建高级添加栏，展开与否取自偏好设置
+func newAdvancedAdd(p fyne.Preferences) *advancedAdd {
+	v := &advancedAdd{due: widget.NewEntry(), priority: widget.NewSelect(priorityLabels, nil), tags: widget.NewEntry(), p: p}
+	v.due.SetPlaceHolder("截止，如 明天 18:00")
+	v.due.Validator = func(s string) error {
+		_, err := parseDueInput(s)
+		return err
+	}
+	v.priority.PlaceHolder = "优先级"
+	v.tags.SetPlaceHolder("标签，以空格分隔")
+	v.row = container.NewGridWithColumns(3, v.due, v.priority, v.tags)
+	v.row.Hidden = !p.Bool(prefAdvancedAdd)
+	v.toggle = widget.NewButtonWithIcon("", theme.MoreVerticalIcon(), func() {
+		v.row.Hidden = !v.row.Hidden
+		p.SetBool(prefAdvancedAdd, !v.row.Hidden)
+		v.paint()
+		v.row.Refresh()
+	})
+	v.paint()
+	return v
+}
+
+// paint 展开时按钮加深，提示高级添加栏正在生效
+func (v *advancedAdd) paint() {
+	v.toggle.Importance = widget.LowImportance
+	if !v.row.Hidden {
+		v.toggle.Importance = widget.MediumImportance
+	}
+	v.toggle.Refresh()
+}
+
+// fields 校验高级添加栏并返回写入新待办的函数，栏位收起时返回空
+func (v *advancedAdd) fields() (func(*Todo), error) {
+	if v.row.Hidden {
+		return nil, nil
+	}
+	due, err := parseDueInput(v.due.Text)
+	if err != nil {
+		return nil, err
+	}
+	priority := Priority(max(v.priority.SelectedIndex(), 0))
+	var tags []string
+	for _, tag := range strings.Fields(v.tags.Text) {
+		if tag = strings.TrimPrefix(tag, "#"); tag != "" {
+			tags = appendTag(tags, tag)
+		}
+	}
+	return func(t *Todo) {
+		if due != nil {
+			t.Due = due
+		}
+		if priority != PriorityNone {
+			t.Priority = priority
+		}
+		for _, tag := range tags {
+			t.Tags = appendTag(t.Tags, tag)
+		}
+		if v.p.Bool(prefNormalizeTags) {
+			t.Tags = normalizeTags(t.Tags)
+		}
+	}, nil
+}
+
+// reset 添加成功后清空各栏
+func (v *advancedAdd) reset() {
+	v.due.SetText("")
+	v.priority.ClearSelected()
+	v.tags.SetText("")
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"log/slog"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/storage"
+)
+
+// openAttachment 用系统默认程序打开附件，文件不存在时弹出提示
+func openAttachment(a fyne.App, win fyne.Window, path string) {
+	if _, err := os.Stat(path); err != nil {
+		showTemporaryPopUp(win.Canvas(), "附件不存在："+path, 3)
+		return
+	}
+	u, err := url.Parse(storage.NewFileURI(path).String())
+	if err != nil {
+		showTemporaryPopUp(win.Canvas(), "无法打开附件："+err.Error(), 3)
+		return
+	}
+	if err := a.OpenURL(u); err != nil {
+		showTemporaryPopUp(win.Canvas(), "无法打开附件："+err.Error(), 3)
+	}
+}
+
+// revealCommand 返回在文件管理器中选中 path 的命令，不支持的系统返回 nil
+func revealCommand(path string) *exec.Cmd {
+	switch runtime.GOOS {
+	case "darwin":
+		return exec.Command("open", "-R", path)
+	case "windows":
+		return exec.Command("explorer", "/select,"+path)
+	case "linux", "freebsd", "openbsd", "netbsd":
+		// freedesktop 约定的文件管理器接口，Nautilus、Dolphin、Nemo 等都支持
+		return exec.Command("dbus-send", "--session", "--type=method_call",
+			"--dest=org.freedesktop.FileManager1", "/org/freedesktop/FileManager1",
+			"org.freedesktop.FileManager1.ShowItems",
+			"array:string:"+storage.NewFileURI(path).String(), "string:")
+	}
+	return nil
+}
+
+// revealAttachment 在文件管理器中显示并选中附件；系统不支持或失败时打开所在的文件夹
+func revealAttachment(a fyne.App, win fyne.Window, path string) {
+	if _, err := os.Stat(path); err != nil {
+		showTemporaryPopUp(win.Canvas(), "附件不存在："+path, 3)
+		return
+	}
+	if cmd := revealCommand(path); cmd != nil {
+		// explorer 即使成功也返回非零退出码，因此在 Windows 上不回退
+		err := cmd.Run()
+		if err == nil || runtime.GOOS == "windows" {
+			return
+		}
+		slog.Debug("reveal failed, opening folder", "file", path, "err", err)
+	}
+	u, err := url.Parse(storage.NewFileURI(filepath.Dir(path)).String())
+	if err == nil {
+		err = a.OpenURL(u)
+	}
+	if err != nil {
+		showTemporaryPopUp(win.Canvas(), "无法打开所在文件夹："+err.Error(), 3)
+	}
+}
+
+// chooseAttachment 弹出文件选择框，选中后回调文件路径
+func chooseAttachment(win fyne.Window, onPick func(path string)) {
+	dialog.ShowFileOpen(func(r fyne.URIReadCloser, err error) {
+		if err != nil {
+			dialog.ShowError(err, win)
+			return
+		}
+		if r == nil {
+			return
+		}
+		r.Close()
+		onPick(r.URI().Path())
+	}, win)
+}
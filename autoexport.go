@@ -0,0 +1,93 @@
+package main
+
+import (
+	"log/slog"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+)
+
+// 自动导出的频率
+const (
+	autoExportOff    = ""
+	autoExportDaily  = cadenceDaily
+	autoExportWeekly = cadenceWeekly
+	autoExportOnQuit = "quit"
+
+	autoExportPrefix      = "todo-auto-" // 自动导出的文件名前缀，轮换时只清理这些文件
+	defaultAutoExportKeep = 7
+	maxAutoExportKeep     = 100
+)
+
+// autoExport 把 todos 与标签颜色导出到偏好设置中的目录，并只保留最近的若干份
+func autoExport(p fyne.Preferences, todos []Todo, colors map[string]string, now time.Time) error {
+	dir := p.String(prefAutoExportDir)
+	ext, data := "json", exportJSON(todos, colors, now)
+	if p.String(prefAutoExportFormat) == "md" {
+		ext, data = "md", exportMarkdown(todos, now)
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	name := filepath.Join(dir, autoExportPrefix+now.Format("20060102-150405")+"."+ext)
+	if err := os.WriteFile(name, data, 0644); err != nil {
+		return err
+	}
+	slog.Debug("auto export done", "file", name)
+
+	// 文件名按时间排序，删除超出保留份数的旧文件
+	keep := p.IntWithFallback(prefAutoExportKeep, defaultAutoExportKeep)
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+	var old []string
+	for _, e := range entries {
+		if strings.HasPrefix(e.Name(), autoExportPrefix) && filepath.Ext(e.Name()) == "."+ext {
+			old = append(old, e.Name())
+		}
+	}
+	slices.Sort(old)
+	for len(old) > keep {
+		if err := os.Remove(filepath.Join(dir, old[0])); err != nil {
+			slog.Warn("remove old export failed", "file", old[0], "err", err)
+		}
+		old = old[1:]
+	}
+	return nil
+}
+
+// startAutoExport 按频率定时导出：启动时与之后每小时检查距上次导出是否已满一个周期，
+// 写文件在后台进行，列表快照在主线程取得；导出成功后才记录时间，失败的下个小时重试
+func startAutoExport(p fyne.Preferences, list func() []Todo) {
+	startHourly(func(bool) {
+		period := cadencePeriod(p.String(prefAutoExportCadence))
+		now := time.Now()
+		if p.String(prefAutoExportDir) == "" || !periodicDue(p, prefAutoExportLast, period, now) {
+			return
+		}
+		todos, colors := cloneTodos(list()), maps.Clone(tagColors)
+		go func() {
+			if err := autoExport(p, todos, colors, now); err != nil {
+				slog.Warn("auto export failed", "dir", p.String(prefAutoExportDir), "err", err)
+				return
+			}
+			fyne.Do(func() { markPeriodic(p, prefAutoExportLast, now) })
+		}()
+	})
+}
+
+// exportOnQuit 选择"退出时导出"时在退出前同步导出一次
+func exportOnQuit(p fyne.Preferences, todos []Todo) {
+	if p.String(prefAutoExportCadence) != autoExportOnQuit || p.String(prefAutoExportDir) == "" {
+		return
+	}
+	if err := autoExport(p, todos, tagColors, time.Now()); err != nil {
+		slog.Warn("auto export failed", "dir", p.String(prefAutoExportDir), "err", err)
+	}
+}
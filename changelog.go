@@ -0,0 +1,57 @@
+package main
+
+import (
+	"encoding/json"
+	"log/slog"
+	"os"
+	"time"
+
+	"fyne.io/fyne/v2"
+)
+
+const (
+	changeLogFile = "changes.log"
+	changeLogMax  = 1 << 20 // 超过 1 MiB 时轮换为 changes.log.1
+)
+
+// changeEntry 修改记录中的一行，每行一个紧凑的 JSON 对象
+type changeEntry struct {
+	Time   time.Time `json:"time"`
+	Action string    `json:"action"`
+	ID     string    `json:"id,omitempty"`
+	Text   string    `json:"text,omitempty"`
+	From   string    `json:"from,omitempty"` // 编辑前的文字，仅在文字变化时记录
+	Note   string    `json:"note,omitempty"` // 完成时记录的结果备注
+}
+
+// logChanges 开启修改记录时把本次操作涉及的待办追加到 changes.log；
+// 只追加不改写，文件过大时保留一份旧记录后重新开始
+func logChanges(p fyne.Preferences, entries ...changeEntry) {
+	if !p.Bool(prefChangeLog) || len(entries) == 0 {
+		return
+	}
+	if fi, err := os.Stat(changeLogFile); err == nil && fi.Size() > changeLogMax {
+		_ = os.Rename(changeLogFile, changeLogFile+".1")
+	}
+	f, err := os.OpenFile(changeLogFile, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		slog.Warn("open change log failed", "file", changeLogFile, "err", err)
+		return
+	}
+	defer f.Close()
+	enc := json.NewEncoder(f)
+	enc.SetEscapeHTML(false)
+	now := time.Now()
+	for _, e := range entries {
+		e.Time = now
+		if err := enc.Encode(e); err != nil {
+			slog.Warn("write change log failed", "file", changeLogFile, "err", err)
+			return
+		}
+	}
+}
+
+// todoChange 由待办生成一条修改记录
+func todoChange(action string, t Todo) changeEntry {
+	return changeEntry{Action: action, ID: t.ID, Text: t.Text}
+}
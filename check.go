@@ -0,0 +1,159 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"unicode/utf8"
+
+	"fyne.io/fyne/v2"
+)
+
+// checkReport check 子命令的检查结果
+type checkReport struct {
+	File          string   `json:"file"`
+	Format        string   `json:"format"`
+	SchemaVersion int      `json:"schemaVersion"`
+	Count         int      `json:"count"`
+	Problems      []string `json:"problems"`
+}
+
+// runCheck 实现 "mytodo check"：校验数据文件而不启动界面，有问题时返回非零退出码。
+// -maxlen 默认取设置中的"最大字数"
+func runCheck(p fyne.Preferences, format string, args []string, out io.Writer) int {
+	fs := flag.NewFlagSet("check", flag.ContinueOnError)
+	asJSON := fs.Bool("json", false, "以 JSON 输出结果")
+	maxLen := fs.Int("maxlen", maxTextLen(p), "每条允许的最大字数，默认取设置中的值")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	r := checkReport{File: storePath(), Format: format, SchemaVersion: schemaVersion, Problems: []string{}}
+	// 正在迁移到压缩格式时数据仍在未压缩的文件中，检查它
+	if js, ok := store.(jsonStore); ok && js.migrate != "" {
+		r.File = js.migrate
+	}
+	problem := func(f string, a ...any) {
+		r.Problems = append(r.Problems, fmt.Sprintf(f, a...))
+	}
+
+	data, err := readDataFile(r.File)
+	switch {
+	case os.IsNotExist(err):
+		problem("数据文件不存在")
+	case err == errChecksum:
+		problem("与校验文件 %s 不符", checksumPath(r.File))
+	case err != nil:
+		problem("读取失败：%v", err)
+	}
+	if data != nil {
+		if d, derr := decodeData(data); derr != nil {
+			problem("%v", derr)
+			data = nil
+		} else {
+			data = d
+		}
+	}
+	if data != nil {
+		if format == "txt" {
+			r.Count = checkText(data, *maxLen, problem)
+		} else {
+			r.Count = checkJSON(data, *maxLen, problem)
+		}
+	}
+
+	if *asJSON {
+		enc := json.NewEncoder(out)
+		enc.SetIndent("", "  ")
+		_ = enc.Encode(r)
+	} else {
+		fmt.Fprintf(out, "%s：格式 %s，版本 %d，共 %d 条\n", r.File, r.Format, r.SchemaVersion, r.Count)
+		if len(r.Problems) == 0 {
+			fmt.Fprintln(out, "未发现问题")
+		}
+		for _, p := range r.Problems {
+			fmt.Fprintln(out, "  - "+p)
+		}
+	}
+	if len(r.Problems) > 0 {
+		return 1
+	}
+	return 0
+}
+
+// checkJSON 逐条解析，发现未知字段、非法日期、超长文字等问题
+func checkJSON(data []byte, maxLen int, problem func(string, ...any)) int {
+	var items []json.RawMessage
+	if err := json.Unmarshal(data, &items); err != nil {
+		problem("JSON 解析失败：%v", err)
+		return 0
+	}
+	for i, raw := range items {
+		n := i + 1
+		var t Todo
+		if err := json.Unmarshal(raw, &t); err != nil {
+			problem("第 %d 条：%v", n, err)
+			continue
+		}
+		dec := json.NewDecoder(bytes.NewReader(raw))
+		dec.DisallowUnknownFields()
+		if err := dec.Decode(&Todo{}); err != nil {
+			problem("第 %d 条：%v", n, err)
+		}
+		checkTodo(n, t, maxLen, problem)
+	}
+	return len(items)
+}
+
+// checkText 检查纯文本格式，每行一条
+func checkText(data []byte, maxLen int, problem func(string, ...any)) int {
+	todos, err := (&textStore{}).parse(data)
+	if err != nil {
+		problem("读取失败：%v", err)
+	}
+	for i, t := range todos {
+		checkTodo(i+1, t, maxLen, problem)
+	}
+	return len(todos)
+}
+
+func checkTodo(n int, t Todo, maxLen int, problem func(string, ...any)) {
+	if t.Text == "" {
+		problem("第 %d 条：内容为空", n)
+	}
+	if l := utf8.RuneCountInString(t.Text); l > maxLen {
+		problem("第 %d 条：%d 字，超过 %d 字上限", n, l, maxLen)
+	}
+	if t.Color != "" {
+		if _, ok := parseHexColor(t.Color); !ok {
+			problem("第 %d 条：颜色值 %q 无效", n, t.Color)
+		}
+	}
+	if t.Priority < PriorityNone || t.Priority > PriorityHigh {
+		problem("第 %d 条：优先级 %d 无效", n, t.Priority)
+	}
+	if t.Status != StatusActive && t.Status != StatusWaiting {
+		problem("第 %d 条：状态 %q 无效", n, t.Status)
+	}
+	if t.Due != nil && t.Due.IsZero() {
+		problem("第 %d 条：截止时间无效", n)
+	}
+	if t.DependsOn != "" && t.DependsOn == t.ID {
+		problem("第 %d 条：不能依赖自己", n)
+	}
+	if t.StartAt != nil && t.StartAt.IsZero() {
+		problem("第 %d 条：开始日期无效", n)
+	}
+	if t.CompletedAt != nil && !t.Done {
+		problem("第 %d 条：未完成却有完成时间", n)
+	}
+	if t.RemindBefore != nil && *t.RemindBefore < 0 {
+		problem("第 %d 条：提前提醒 %d 分钟无效", n, *t.RemindBefore)
+	}
+	if t.Sound != "" && !knownSound(t.Sound) {
+		problem("第 %d 条：未知的提醒声音 %q", n, t.Sound)
+	}
+}
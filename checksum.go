@@ -0,0 +1,102 @@
+package main
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// errChecksum 数据文件与 .sha256 校验文件不符，内容仍会返回供调用方决定是否使用
+var errChecksum = errors.New("数据文件校验失败")
+
+// errUnreadable 数据文件无法解析（多为写入或同步不完整）且存在备份，调用方可以提示恢复
+var errUnreadable = errors.New("数据文件无法解析")
+
+func checksumPath(path string) string { return path + ".sha256" }
+func backupPath(path string) string   { return path + ".bak" }
+
+// checksumLine 生成与 sha256sum 兼容的校验行，便于用 sha256sum -c 手动核对
+func checksumLine(path string, data []byte) []byte {
+	sum := sha256.Sum256(data)
+	return []byte(fmt.Sprintf("%s  %s\n", hex.EncodeToString(sum[:]), filepath.Base(path)))
+}
+
+// verifyData 校验内容，没有校验文件时视为通过（旧版本生成的数据）
+func verifyData(path string, data []byte) bool {
+	want, err := os.ReadFile(checksumPath(path))
+	if err != nil {
+		return true
+	}
+	return bytes.Equal(bytes.TrimSpace(want), bytes.TrimSpace(checksumLine(path, data)))
+}
+
+// readDataFile 读取数据文件并校验，不符时同时返回内容和 errChecksum
+func readDataFile(path string) ([]byte, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if !verifyData(path, data) {
+		return data, errChecksum
+	}
+	return data, nil
+}
+
+// writeDataFile 写入数据文件并更新校验文件；旧文件校验通过时先保留为 .bak
+func writeDataFile(path string, data []byte) error {
+	if old, err := os.ReadFile(path); err == nil && verifyData(path, old) {
+		_ = os.WriteFile(backupPath(path), old, 0644)
+		_ = os.WriteFile(checksumPath(backupPath(path)), checksumLine(backupPath(path), old), 0644)
+	}
+	if err := writeFileAtomic(path, data); err != nil {
+		return err
+	}
+	return os.WriteFile(checksumPath(path), checksumLine(path, data), 0644)
+}
+
+// writeFileAtomic 先写入同目录的临时文件再改名，中途退出或断电时不会留下写了一半的数据文件
+func writeFileAtomic(path string, data []byte) error {
+	f, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp*")
+	if err != nil {
+		return err
+	}
+	_, err = f.Write(data)
+	if err == nil {
+		err = f.Sync()
+	}
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil {
+		err = os.Chmod(f.Name(), 0644)
+	}
+	if err == nil {
+		err = os.Rename(f.Name(), path)
+	}
+	if err != nil {
+		_ = os.Remove(f.Name())
+	}
+	return err
+}
+
+// hasBackup 判断是否存在可用于恢复的备份
+func hasBackup(path string) bool {
+	_, err := os.Stat(backupPath(path))
+	return err == nil
+}
+
+// restoreBackup 用 .bak 覆盖数据文件，不再轮换备份
+func restoreBackup(path string) error {
+	data, err := readDataFile(backupPath(path))
+	if err != nil {
+		return err
+	}
+	if err := writeFileAtomic(path, data); err != nil {
+		return err
+	}
+	return os.WriteFile(checksumPath(path), checksumLine(path, data), 0644)
+}
@@ -0,0 +1,109 @@
+package main
+
+import (
+	"image/color"
+	"strconv"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+)
+
+// labelColors 颜色标签的预设色板
+var labelColors = []struct {
+	Name string
+	Hex  string
+}{
+	{"红色", "#e53935"},
+	{"橙色", "#fb8c00"},
+	{"黄色", "#fdd835"},
+	{"绿色", "#43a047"},
+	{"蓝色", "#1e88e5"},
+	{"紫色", "#8e24aa"},
+	{"灰色", "#757575"},
+}
+
+// parseHexColor 解析 #RGB 或 #RRGGBB，非法值返回 false
+func parseHexColor(s string) (color.Color, bool) {
+	s = strings.TrimPrefix(strings.TrimSpace(s), "#")
+	if len(s) == 3 {
+		s = string([]byte{s[0], s[0], s[1], s[1], s[2], s[2]})
+	}
+	if len(s) != 6 {
+		return nil, false
+	}
+	v, err := strconv.ParseUint(s, 16, 32)
+	if err != nil {
+		return nil, false
+	}
+	return color.NRGBA{R: uint8(v >> 16), G: uint8(v >> 8), B: uint8(v), A: 255}, true
+}
+
+// sameColor 判断两个颜色值是否相同，忽略大小写与简写形式
+func sameColor(a, b string) bool {
+	ca, okA := parseHexColor(a)
+	cb, okB := parseHexColor(b)
+	if !okA || !okB {
+		return !okA && !okB
+	}
+	return ca == cb
+}
+
+// colorBar 行首的颜色条，无颜色或颜色非法时返回 nil
+func colorBar(hex string) fyne.CanvasObject {
+	c, ok := parseHexColor(hex)
+	if !ok {
+		return nil
+	}
+	bar := canvas.NewRectangle(c)
+	bar.SetMinSize(fyne.NewSize(4, 0))
+	return bar
+}
+
+// showColorPicker 弹出色板，选择后以十六进制回调，"无" 回调空字符串
+func showColorPicker(win fyne.Window, onPick func(hex string)) {
+	var d dialog.Dialog
+	swatches := container.NewGridWithColumns(4)
+	for _, lc := range labelColors {
+		c, _ := parseHexColor(lc.Hex)
+		hex := lc.Hex
+		btn := widget.NewButton("", func() {
+			d.Hide()
+			onPick(hex)
+		})
+		swatch := canvas.NewRectangle(c)
+		swatch.CornerRadius = 4
+		swatches.Add(container.NewStack(btn, container.NewPadded(swatch)))
+	}
+	swatches.Add(widget.NewButton("无", func() {
+		d.Hide()
+		onPick("")
+	}))
+	d = dialog.NewCustom("颜色标签", "取消", swatches, win)
+	d.Show()
+}
+
+// colorFilterOptions 颜色筛选下拉框的选项，第一个为不筛选
+func colorFilterOptions() []string {
+	opts := []string{"全部颜色", "无颜色"}
+	for _, lc := range labelColors {
+		opts = append(opts, lc.Name)
+	}
+	return opts
+}
+
+// colorFilterHex 把筛选选项转换为颜色值，ok 为 false 表示不筛选
+func colorFilterHex(option string) (hex string, ok bool) {
+	if option == "无颜色" {
+		return "", true
+	}
+	for _, lc := range labelColors {
+		if lc.Name == option {
+			return lc.Hex, true
+		}
+	}
+	return "", false
+}
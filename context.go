@@ -0,0 +1,63 @@
+package main
+
+import (
+	"slices"
+	"strings"
+)
+
+// defaultContexts 情境选择菜单中始终列出的常用情境
+var defaultContexts = []string{"@家", "@办公室", "@电话", "@电脑", "@外出"}
+
+// 情境筛选中不属于具体情境的两个选项
+const (
+	contextAll  = "全部情境"
+	contextNone = "无情境"
+)
+
+// usedContexts 列表中出现过的情境，按名称排序
+func usedContexts(todos []Todo) []string {
+	var used []string
+	for _, t := range todos {
+		if t.Context != "" && !slices.Contains(used, t.Context) {
+			used = append(used, t.Context)
+		}
+	}
+	slices.Sort(used)
+	return used
+}
+
+// contextChoices 情境选择菜单的选项：常用情境在前，其余已用的情境在后
+func contextChoices(todos []Todo) []string {
+	choices := append([]string(nil), defaultContexts...)
+	for _, c := range usedContexts(todos) {
+		if !slices.Contains(choices, c) {
+			choices = append(choices, c)
+		}
+	}
+	return choices
+}
+
+// contextFilterOptions 情境筛选下拉框的选项，只列出正在使用的情境
+func contextFilterOptions(todos []Todo) []string {
+	return append([]string{contextAll, contextNone}, usedContexts(todos)...)
+}
+
+// contextMatch 判断待办是否符合所选情境
+func contextMatch(option string, t Todo) bool {
+	switch option {
+	case "", contextAll:
+		return true
+	case contextNone:
+		return t.Context == ""
+	}
+	return t.Context == option
+}
+
+// normalizeContext 整理用户输入的情境名，统一加上 @ 前缀
+func normalizeContext(s string) string {
+	s = strings.TrimPrefix(normalizeSpace(s), "@")
+	if s == "" {
+		return ""
+	}
+	return "@" + s
+}
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net"
+	"os"
+	"time"
+
+	"fyne.io/fyne/v2"
+)
+
+// controlSocket 控制接口的 Unix 套接字，与数据文件放在同一目录
+const controlSocket = "todo.sock"
+
+// controlRequest 控制接口的请求，每行一个 JSON 对象：
+//
+//	{"cmd":"list"}                     列出全部待办
+//	{"cmd":"count"}                    未完成的条数
+//	{"cmd":"add","text":"买牛奶"}       添加一条，text 按输入框的规则解析
+//	{"cmd":"complete","id":"<ID>"}     完成一条
+//
+// 每个请求回复一行 controlResponse，失败时 ok 为 false 并附带 error
+type controlRequest struct {
+	Cmd  string `json:"cmd"`
+	Text string `json:"text,omitempty"`
+	ID   string `json:"id,omitempty"`
+}
+
+type controlResponse struct {
+	OK    bool   `json:"ok"`
+	Error string `json:"error,omitempty"`
+	Todos []Todo `json:"todos,omitempty"`
+	Count *int   `json:"count,omitempty"`
+	ID    string `json:"id,omitempty"` // add 成功时为新待办的 ID
+}
+
+// controlTimeout 单个连接空闲多久后断开
+const controlTimeout = time.Minute
+
+// startControl 监听控制套接字，每个请求在主线程中交给 handle 处理。
+// 已有实例在监听时返回错误；返回的函数关闭监听并删除套接字文件
+func startControl(path string, handle func(controlRequest) controlResponse) (func(), error) {
+	if conn, err := net.Dial("unix", path); err == nil {
+		conn.Close()
+		return nil, errors.New("control socket already in use")
+	}
+	// 上次异常退出留下的套接字文件会导致监听失败
+	_ = os.Remove(path)
+	ln, err := net.Listen("unix", path)
+	if err != nil {
+		return nil, err
+	}
+	_ = os.Chmod(path, 0600)
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				if !errors.Is(err, net.ErrClosed) {
+					slog.Warn("control accept failed", "err", err)
+				}
+				return
+			}
+			go serveControl(conn, handle)
+		}
+	}()
+	slog.Debug("control socket listening", "path", path)
+	return func() { ln.Close() }, nil
+}
+
+func serveControl(conn net.Conn, handle func(controlRequest) controlResponse) {
+	defer conn.Close()
+	sc := bufio.NewScanner(conn)
+	enc := json.NewEncoder(conn)
+	for {
+		_ = conn.SetReadDeadline(time.Now().Add(controlTimeout))
+		if !sc.Scan() {
+			return
+		}
+		var req controlRequest
+		var resp controlResponse
+		if err := json.Unmarshal(sc.Bytes(), &req); err != nil {
+			resp = controlResponse{Error: "invalid request: " + err.Error()}
+		} else {
+			fyne.DoAndWait(func() { resp = handle(req) })
+		}
+		slog.Debug("control request", "cmd", req.Cmd, "ok", resp.OK)
+		if err := enc.Encode(resp); err != nil {
+			return
+		}
+	}
+}
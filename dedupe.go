@@ -0,0 +1,121 @@
+package main
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+)
+
+// dedupeKey 比较重复项时使用的文字；loose 时忽略大小写与多余空白
+func dedupeKey(text string, loose bool) string {
+	if loose {
+		return strings.ToLower(normalizeSpace(text))
+	}
+	return text
+}
+
+// dedupe 合并文字相同的未完成待办，每组保留创建最早的一条，位置取该组第一次出现处。
+// 已完成的不参与比较，以免删掉未完成的而留下已完成的。返回合并后的列表与被移除的条目
+func dedupe(todos []Todo, loose bool) (kept, removed []Todo) {
+	first := map[string]int{} // key → kept 中的下标
+	for _, t := range todos {
+		if t.Done {
+			kept = append(kept, t)
+			continue
+		}
+		key := dedupeKey(t.Text, loose)
+		i, ok := first[key]
+		if !ok {
+			first[key] = len(kept)
+			kept = append(kept, t)
+			continue
+		}
+		if t.CreatedAt.Before(kept[i].CreatedAt) {
+			kept[i], t = t, kept[i]
+		}
+		removed = append(removed, t)
+	}
+	return kept, removed
+}
+
+// showDedupeDialog 预览将被移除的重复项，确认后以这些条目的 ID 调用 onApply；
+// 对话框打开期间列表可能变化，由调用方按 ID 从当前列表中移除
+func showDedupeDialog(win fyne.Window, todos []Todo, onApply func(ids []string)) {
+	var removed []Todo
+	summary := widget.NewLabel("")
+	preview := container.NewVBox()
+	loose := widget.NewCheck("忽略大小写和多余空白", nil)
+	update := func() {
+		_, removed = dedupe(todos, loose.Checked)
+		summary.SetText(fmt.Sprintf("将移除 %d 条重复项，每组保留最早创建的一条", len(removed)))
+		if len(removed) == 0 {
+			summary.SetText("没有发现重复项")
+		}
+		preview.Objects = nil
+		for _, t := range removed {
+			l := widget.NewLabel(t.Text)
+			l.Truncation = fyne.TextTruncateEllipsis
+			preview.Add(l)
+		}
+		preview.Refresh()
+	}
+	loose.OnChanged = func(bool) { update() }
+	update()
+
+	content := container.NewBorder(container.NewVBox(loose, summary, widget.NewSeparator()), nil, nil, nil,
+		container.NewVScroll(preview))
+	d := dialog.NewCustomConfirm("合并重复项", "合并", "取消", content, func(ok bool) {
+		if ok && len(removed) > 0 {
+			var ids []string
+			for _, t := range removed {
+				ids = append(ids, t.ID)
+			}
+			onApply(ids)
+		}
+	}, win)
+	d.Resize(fyne.NewSize(win.Canvas().Size().Width*0.9, win.Canvas().Size().Height*0.8))
+	d.Show()
+}
+
+// mergeTodo 把 src 中 dst 尚未设置的字段补到 dst 上，标签取两者的并集；
+// dst 已有的内容保持不变。返回 dst 是否有变化
+func mergeTodo(dst *Todo, src Todo) bool {
+	before := cloneTodos([]Todo{*dst})[0]
+	if dst.Color == "" {
+		dst.Color = src.Color
+	}
+	if dst.Attachment == "" {
+		dst.Attachment = src.Attachment
+	}
+	if dst.Priority == PriorityNone {
+		dst.Priority = src.Priority
+	}
+	if dst.Due == nil {
+		dst.Due = src.Due
+	}
+	if dst.StartAt == nil {
+		dst.StartAt = src.StartAt
+	}
+	if dst.Context == "" {
+		dst.Context = src.Context
+	}
+	if dst.RemindBefore == nil {
+		dst.RemindBefore = src.RemindBefore
+	}
+	if dst.Sound == "" {
+		dst.Sound = src.Sound
+	}
+	if dst.Status == "" {
+		dst.Status = src.Status
+	}
+	dst.HideInTray = dst.HideInTray || src.HideInTray
+	for _, tag := range src.Tags {
+		dst.Tags = appendTag(dst.Tags, tag)
+	}
+	return !reflect.DeepEqual(before, *dst)
+}
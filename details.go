@@ -0,0 +1,218 @@
+package main
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/widget"
+)
+
+// splitMinWidth 窗口宽度低于此值时只显示列表，不显示详情面板
+const splitMinWidth = 640
+
+// priorityLabels 详情面板中优先级下拉框的选项，下标即 Priority 的值
+var priorityLabels = []string{"无", "低", "中", "高"}
+
+// priorityImportance 列表行中优先级标记使用的颜色
+var priorityImportance = map[Priority]widget.Importance{
+	PriorityNone:   widget.LowImportance,
+	PriorityLow:    widget.SuccessImportance,
+	PriorityMedium: widget.WarningImportance,
+	PriorityHigh:   widget.DangerImportance,
+}
+
+// priorityBadge 优先级标记的文字，未设置时显示占位符
+func priorityBadge(p Priority) string {
+	if p <= PriorityNone || p > PriorityHigh {
+		return "·"
+	}
+	return priorityLabels[p]
+}
+
+// detailPane 分栏模式右侧的详情面板，编辑选中待办的文字、优先级、截止时间和标签
+type detailPane struct {
+	content *fyne.Container
+	id      string
+	updated time.Time // 显示时待办的修改时间，用于判断是否需要刷新
+
+	text     *widget.Entry
+	priority *widget.Select
+	due      *widget.Entry
+	tags     *widget.Entry
+	info     *widget.Label
+	form     fyne.CanvasObject
+	empty    fyne.CanvasObject
+}
+
+// newDetailPane 点击保存或在单行输入框中回车时，以选中待办的 ID 和修改函数调用 onSave
+func newDetailPane(p fyne.Preferences, onSave func(id string, change func(*Todo))) *detailPane {
+	d := &detailPane{
+		text:     widget.NewMultiLineEntry(),
+		priority: widget.NewSelect(priorityLabels, nil),
+		due:      widget.NewEntry(),
+		tags:     widget.NewEntry(),
+		info:     widget.NewLabel(""),
+	}
+	d.text.Wrapping = fyne.TextWrapWord
+	d.text.Validator = func(s string) error {
+		_, err := checkTodoText(p, s)
+		return err
+	}
+	d.due.SetPlaceHolder("如 明天、2026-01-02 18:00，留空为无")
+	d.due.Validator = func(s string) error {
+		_, err := parseDueInput(s)
+		return err
+	}
+	d.tags.SetPlaceHolder("以空格分隔")
+	d.info.Importance = widget.LowImportance
+	d.info.Wrapping = fyne.TextWrapWord
+
+	save := func() {
+		if d.id == "" || d.text.Validate() != nil || d.due.Validate() != nil {
+			return
+		}
+		text, _ := checkTodoText(p, d.text.Text)
+		due, _ := parseDueInput(d.due.Text)
+		priority := Priority(max(d.priority.SelectedIndex(), 0))
+		var tags []string
+		for _, tag := range strings.Fields(d.tags.Text) {
+			if tag = strings.TrimPrefix(tag, "#"); tag != "" {
+				tags = appendTag(tags, tag)
+			}
+		}
+		if p.Bool(prefNormalizeTags) {
+			tags = normalizeTags(tags)
+		}
+		onSave(d.id, func(t *Todo) {
+			t.Text, t.Priority, t.Due, t.Tags = text, priority, due, tags
+		})
+	}
+	saveBtn := widget.NewButton("保存", save)
+	saveBtn.Importance = widget.HighImportance
+	d.due.OnSubmitted = func(string) { save() }
+	d.tags.OnSubmitted = func(string) { save() }
+
+	d.form = container.NewBorder(nil, container.NewVBox(d.info, saveBtn), nil, nil,
+		widget.NewForm(
+			widget.NewFormItem("内容", d.text),
+			widget.NewFormItem("优先级", d.priority),
+			widget.NewFormItem("截止", d.due),
+			widget.NewFormItem("标签", d.tags),
+		))
+	empty := widget.NewLabel("点击左侧的待办查看详情")
+	empty.Importance = widget.LowImportance
+	d.empty = container.NewCenter(empty)
+	d.content = container.NewPadded(d.empty)
+	return d
+}
+
+// show 在面板中显示并编辑 t
+func (d *detailPane) show(t Todo) {
+	d.id, d.updated = t.ID, t.UpdatedAt
+	d.text.SetText(t.Text)
+	d.priority.SetSelectedIndex(int(t.Priority))
+	d.due.SetText("")
+	if t.Due != nil {
+		d.due.SetText(formatDueInput(*t.Due))
+	}
+	d.tags.SetText(strings.Join(t.Tags, " "))
+	d.info.SetText(t.detailText())
+	d.content.Objects = []fyne.CanvasObject{d.form}
+	d.content.Refresh()
+}
+
+// sync 列表刷新后调用：选中的待办已不存在时清空，被修改过时重新显示，
+// 其它情况保留面板中尚未保存的输入
+func (d *detailPane) sync(todos []Todo) {
+	if d.id == "" {
+		return
+	}
+	for _, t := range todos {
+		if t.ID == d.id {
+			if !t.UpdatedAt.Equal(d.updated) {
+				d.show(t)
+			}
+			return
+		}
+	}
+	d.clear()
+}
+
+// clear 选中的待办被完成或删除后清空面板
+func (d *detailPane) clear() {
+	d.id = ""
+	d.content.Objects = []fyne.CanvasObject{d.empty}
+	d.content.Refresh()
+}
+
+// parseDueInput 解析详情面板中的截止时间，空白表示没有截止时间
+func parseDueInput(s string) (*time.Time, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return nil, nil
+	}
+	due, ok := parseDue(strings.Join(strings.Fields(s), "T"), time.Now())
+	if !ok {
+		return nil, errors.New("无法识别的日期，例如 明天、01-02、2026-01-02 18:00")
+	}
+	due = due.UTC()
+	return &due, nil
+}
+
+// formatDueInput 把截止时间写回输入框，23:59 视为全天只写日期
+func formatDueInput(due time.Time) string {
+	due = due.In(time.Local)
+	if due.Hour() == 23 && due.Minute() == 59 {
+		return due.Format("2006-01-02")
+	}
+	return due.Format("2006-01-02 15:04")
+}
+
+// adaptiveSplit 宽度足够时左右分栏显示，窗口变窄时只显示左侧的列表
+type adaptiveSplit struct {
+	widget.BaseWidget
+	split   *container.Split
+	content *fyne.Container
+	enabled bool
+	wide    bool
+}
+
+func newAdaptiveSplit(leading, trailing fyne.CanvasObject) *adaptiveSplit {
+	s := &adaptiveSplit{split: container.NewHSplit(leading, trailing)}
+	s.split.Offset = 0.5
+	s.content = container.NewStack(leading)
+	s.ExtendBaseWidget(s)
+	return s
+}
+
+func (s *adaptiveSplit) CreateRenderer() fyne.WidgetRenderer {
+	return widget.NewSimpleRenderer(s.content)
+}
+
+// setEnabled 开关分栏；关闭时无论宽度都只显示列表
+func (s *adaptiveSplit) setEnabled(on bool) {
+	s.enabled = on
+	s.update(s.Size().Width)
+}
+
+func (s *adaptiveSplit) Resize(size fyne.Size) {
+	s.update(size.Width)
+	s.BaseWidget.Resize(size)
+}
+
+func (s *adaptiveSplit) update(width float32) {
+	wide := s.enabled && width >= splitMinWidth
+	if wide == s.wide && len(s.content.Objects) > 0 {
+		return
+	}
+	s.wide = wide
+	if wide {
+		s.content.Objects = []fyne.CanvasObject{s.split}
+	} else {
+		s.content.Objects = []fyne.CanvasObject{s.split.Leading}
+	}
+	s.content.Refresh()
+}
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/dialog"
+)
+
+// exportFile JSON 导出文件的结构：完整保留所有字段，并记录格式版本以便以后导入
+type exportFile struct {
+	SchemaVersion int               `json:"schemaVersion"`
+	ExportedAt    time.Time         `json:"exportedAt"`
+	Todos         json.RawMessage   `json:"todos"`
+	TagColors     map[string]string `json:"tagColors,omitempty"` // 标签登记表中的颜色
+}
+
+// exportJSON 生成 JSON 导出内容，待办部分与 saveTodos 写入的内容一致，colors 为标签颜色
+func exportJSON(todos []Todo, colors map[string]string, now time.Time) []byte {
+	data, _ := json.MarshalIndent(exportFile{
+		SchemaVersion: schemaVersion,
+		ExportedAt:    now.UTC(),
+		Todos:         encodeTodos(todos),
+		TagColors:     colors,
+	}, "", "  ")
+	return data
+}
+
+// showExportDialog 选择保存位置并写入 data，name 为建议的文件名；
+// 选中已有文件时由保存对话框先确认是否覆盖，写入成功后提示最终路径
+func showExportDialog(win fyne.Window, name string, data []byte) {
+	d := dialog.NewFileSave(func(w fyne.URIWriteCloser, err error) {
+		if err != nil {
+			dialog.ShowError(err, win)
+			return
+		}
+		if w == nil {
+			return
+		}
+		defer w.Close()
+		if _, err := w.Write(data); err != nil {
+			dialog.ShowError(err, win)
+			return
+		}
+		showTemporaryPopUp(win.Canvas(), "已导出到 "+w.URI().Path(), 3)
+	}, win)
+	d.SetFileName(name)
+	d.Show()
+}
+
+// exportName 生成带日期的导出文件名；开启 prefExportStamp 时再加上时分秒，
+// 同一天多次导出也不会选中已有的文件
+func exportName(p fyne.Preferences, ext string, now time.Time) string {
+	if p.Bool(prefExportStamp) {
+		return fmt.Sprintf("todo-%s.%s", now.Format("20060102-150405"), ext)
+	}
+	return fmt.Sprintf("todo-%s.%s", now.Format("20060102"), ext)
+}
+
+// exportMarkdown 生成 Markdown 清单，每条一行，优先级、截止时间、标签与情境写为行内标记；
+// 已完成的写为 "- [x]"，与 parseImport 的识别方式一致，导入后能还原这些字段
+func exportMarkdown(todos []Todo, now time.Time) []byte {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# 待办事项（%s）\n\n", now.Format("2006-01-02"))
+	for _, t := range todos {
+		line := strings.ReplaceAll(t.Text, "\n", " ")
+		if tokens := markdownTokens(t); tokens != "" {
+			line += " " + tokens
+		}
+		box := " "
+		if t.Done {
+			box = "x"
+		}
+		fmt.Fprintf(&b, "- [%s] %s\n", box, line)
+	}
+	return []byte(b.String())
+}
+
+// markdownTokens 把字段写成 parseTokens 能识别的标记：截止时间使用绝对日期，
+// 当天 23:59 只写日期；情境放在最后，导入时取最后一个 @ 开头的词
+func markdownTokens(t Todo) string {
+	var parts []string
+	if t.Priority != PriorityNone {
+		parts = append(parts, "!"+t.Priority.String())
+	}
+	if t.Due != nil {
+		due := t.Due.In(time.Local)
+		if due.Hour() == 23 && due.Minute() == 59 {
+			parts = append(parts, "@"+due.Format("2006-01-02"))
+		} else {
+			parts = append(parts, "@"+due.Format("2006-01-02T15:04"))
+		}
+	}
+	for _, tag := range t.Tags {
+		parts = append(parts, "#"+tag)
+	}
+	if t.Context != "" {
+		parts = append(parts, t.Context)
+	}
+	return strings.Join(parts, " ")
+}
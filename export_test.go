@@ -0,0 +1,86 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestMarkdownRoundTrip(t *testing.T) {
+	now := time.Date(2026, 1, 1, 9, 0, 0, 0, time.Local)
+	due := func(s string) *time.Time {
+		d, err := time.ParseInLocation("2006-01-02 15:04", s, time.Local)
+		if err != nil {
+			t.Fatal(err)
+		}
+		d = d.UTC()
+		return &d
+	}
+	todos := []Todo{
+		{Text: "买牛奶"},
+		{Text: "写周报", Priority: PriorityHigh, Due: due("2026-01-02 18:00"), Tags: []string{"工作", "每周"}},
+		{Text: "给妈妈打电话", Due: due("2026-03-01 23:59"), Context: "@电话"},
+		{Text: "交房租", Priority: PriorityLow, Tags: []string{"家"}, Done: true},
+	}
+
+	got, err := parseImport(exportMarkdown(todos, now), "text/markdown")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != len(todos) {
+		t.Fatalf("got %d items, want %d", len(got), len(todos))
+	}
+	for i, want := range todos {
+		g := got[i]
+		if g.Text != want.Text || g.Priority != want.Priority || g.Context != want.Context || g.Done != want.Done {
+			t.Errorf("item %d: got %+v, want %+v", i, g, want)
+		}
+		if !reflect.DeepEqual(g.Tags, want.Tags) {
+			t.Errorf("item %d: tags %v, want %v", i, g.Tags, want.Tags)
+		}
+		switch {
+		case (g.Due == nil) != (want.Due == nil):
+			t.Errorf("item %d: due %v, want %v", i, g.Due, want.Due)
+		case g.Due != nil && !g.Due.Equal(*want.Due):
+			t.Errorf("item %d: due %v, want %v", i, *g.Due, *want.Due)
+		}
+	}
+}
+
+func TestParseMarkdownItemOnlyTokens(t *testing.T) {
+	got := parseMarkdownItem("!高 #工作", time.Now())
+	if got.Text != "!高 #工作" || got.Priority != PriorityNone || got.Tags != nil {
+		t.Errorf("got %+v, want the line kept as text", got)
+	}
+}
+
+func TestParseImportHeadings(t *testing.T) {
+	data := "# 清单\n## 工作\n#工作 写周报\n- #家 交房租\n#\n买牛奶\n"
+	got, err := parseImport([]byte(data), "text/plain")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var texts []string
+	for _, todo := range got {
+		texts = append(texts, todo.Text)
+	}
+	want := []string{"#工作 写周报", "交房租", "买牛奶"}
+	if !reflect.DeepEqual(texts, want) {
+		t.Errorf("got %q, want %q", texts, want)
+	}
+}
+
+func TestEscapeICS(t *testing.T) {
+	tests := []struct{ in, want string }{
+		{`a\b;c,d`, `a\\b\;c\,d`},
+		{"一\n二", `一\n二`},
+		{"一\r\n二", `一\n二`},
+		{"一\r二", `一\n二`},
+		{"一\r\r\n二", `一\n\n二`},
+	}
+	for _, tt := range tests {
+		if got := escapeICS(tt.in); got != tt.want {
+			t.Errorf("escapeICS(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
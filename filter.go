@@ -0,0 +1,43 @@
+package main
+
+// 多个筛选条件同时生效时的组合方式
+const (
+	filterAnd = "and" // 同时满足全部条件
+	filterOr  = "or"  // 满足任一条件
+)
+
+var filterModes = []struct{ mode, label string }{
+	{filterAnd, "同时满足（且）"},
+	{filterOr, "满足任一（或）"},
+}
+
+// filterResult 一个筛选条件对某条待办的结果，active 为用户是否选择了该条件
+type filterResult struct {
+	active, match bool
+}
+
+// matchFilters 按组合方式判断是否显示。"且"要求每个条件都满足，未选择的条件
+// 按其默认值判断（如"全部"视图仍隐藏推迟的待办）；"或"只看已选择的条件，
+// 一个都没选时与"且"相同
+func matchFilters(mode string, filters ...filterResult) bool {
+	if mode == filterOr {
+		selected := false
+		for _, f := range filters {
+			if f.active {
+				if f.match {
+					return true
+				}
+				selected = true
+			}
+		}
+		if selected {
+			return false
+		}
+	}
+	for _, f := range filters {
+		if !f.match {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/layout"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+// focusIndex 返回专注模式下应显示的待办下标：跳过已完成、等待中、受阻和未到开始日期的，取优先级最高者，
+// 同级取靠前的；没有可执行的待办时返回 -1
+func focusIndex(todos []Todo) int {
+	best := -1
+	now := time.Now()
+	for i, t := range todos {
+		if _, blocked := blockedBy(t, todos); t.Done || t.Status == StatusWaiting || t.deferred(now) || blocked {
+			continue
+		}
+		if best < 0 || t.Priority > todos[best].Priority {
+			best = i
+		}
+	}
+	return best
+}
+
+// focusView 专注模式视图：居中大字显示一条待办
+type focusView struct {
+	content fyne.CanvasObject
+	text    *widget.Label
+	rest    *widget.Label
+	done    *widget.Button
+}
+
+func newFocusView(onDone, onExit func()) *focusView {
+	v := &focusView{
+		text: widget.NewLabelWithStyle("", fyne.TextAlignCenter, fyne.TextStyle{Bold: true}),
+		rest: widget.NewLabelWithStyle("", fyne.TextAlignCenter, fyne.TextStyle{}),
+	}
+	v.text.Wrapping = fyne.TextWrapWord
+	v.text.SizeName = theme.SizeNameHeadingText
+	v.rest.Importance = widget.LowImportance
+
+	v.done = widget.NewButtonWithIcon("完成，下一条", theme.ConfirmIcon(), onDone)
+	v.done.Importance = widget.HighImportance
+	exit := widget.NewButtonWithIcon("返回列表", theme.NavigateBackIcon(), onExit)
+	exit.Importance = widget.LowImportance
+
+	card := widget.NewCard("", "", container.NewVBox(v.text, v.rest, v.done))
+	// 卡片占满宽度以便长文字换行，上下留白使其垂直居中
+	v.content = container.NewBorder(nil, container.NewHBox(exit), nil, nil,
+		container.NewPadded(container.NewVBox(layout.NewSpacer(), card, layout.NewSpacer())))
+	return v
+}
+
+// update 按当前待办刷新专注视图
+func (v *focusView) update(todos []Todo) {
+	i := focusIndex(todos)
+	if i < 0 {
+		v.text.SetText("全部完成 🎉")
+		v.rest.SetText("")
+		v.done.Disable()
+		return
+	}
+	v.text.SetText(todos[i].Text)
+	if n := actionableCount(todos) - 1; n > 0 {
+		v.rest.SetText(fmt.Sprintf("之后还有 %d 条", n))
+	} else {
+		v.rest.SetText("这是最后一条")
+	}
+	v.done.Enable()
+}
+
+// actionableCount 统计未完成、不在等待中、未受阻且已到开始日期的待办数量
+func actionableCount(todos []Todo) int {
+	n := 0
+	now := time.Now()
+	for _, t := range todos {
+		if _, blocked := blockedBy(t, todos); !t.Done && t.Status != StatusWaiting && !t.deferred(now) && !blocked {
+			n++
+		}
+	}
+	return n
+}
@@ -0,0 +1,70 @@
+package main
+
+// maxHistory 撤销栈保留的最大步数
+const maxHistory = 100
+
+// snapshot 记录一次操作前的完整列表，label 用于提示
+type snapshot struct {
+	label string
+	todos []Todo
+}
+
+// history 有界的撤销/重做栈，每一步保存操作前的列表快照
+type history struct {
+	undos, redos []snapshot
+}
+
+// record 在修改列表之前调用，新操作会清空重做栈
+func (h *history) record(label string, todos []Todo) {
+	h.undos = append(h.undos, snapshot{label, cloneTodos(todos)})
+	if len(h.undos) > maxHistory {
+		h.undos = h.undos[len(h.undos)-maxHistory:]
+	}
+	h.redos = nil
+}
+
+// undo 返回上一步的列表，并把当前列表移入重做栈
+func (h *history) undo(current []Todo) ([]Todo, string, bool) {
+	return h.step(&h.undos, &h.redos, current)
+}
+
+// redo 返回撤销前的列表，并把当前列表移回撤销栈
+func (h *history) redo(current []Todo) ([]Todo, string, bool) {
+	return h.step(&h.redos, &h.undos, current)
+}
+
+func (h *history) step(from, to *[]snapshot, current []Todo) ([]Todo, string, bool) {
+	if len(*from) == 0 {
+		return nil, "", false
+	}
+	s := (*from)[len(*from)-1]
+	*from = (*from)[:len(*from)-1]
+	*to = append(*to, snapshot{s.label, cloneTodos(current)})
+	return s.todos, s.label, true
+}
+
+// cloneTodos 深拷贝列表，避免快照与当前列表共用指针和切片字段
+func cloneTodos(todos []Todo) []Todo {
+	out := make([]Todo, len(todos))
+	for i, t := range todos {
+		if t.Due != nil {
+			due := *t.Due
+			t.Due = &due
+		}
+		if t.StartAt != nil {
+			start := *t.StartAt
+			t.StartAt = &start
+		}
+		if t.RemindBefore != nil {
+			minutes := *t.RemindBefore
+			t.RemindBefore = &minutes
+		}
+		if t.CompletedAt != nil {
+			at := *t.CompletedAt
+			t.CompletedAt = &at
+		}
+		t.Tags = append([]string(nil), t.Tags...)
+		out[i] = t
+	}
+	return out
+}
@@ -0,0 +1,103 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os/exec"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+)
+
+// hookTimeout 完成钩子命令的最长运行时间
+const hookTimeout = 30 * time.Second
+
+// splitCommand 按空白拆分命令模板，支持用单引号或双引号包住含空格的参数
+func splitCommand(s string) ([]string, error) {
+	var args []string
+	var cur strings.Builder
+	inArg := false
+	var quote rune
+	for _, r := range s {
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			} else {
+				cur.WriteRune(r)
+			}
+		case r == '"' || r == '\'':
+			quote, inArg = r, true
+		case r == ' ' || r == '\t':
+			if inArg {
+				args = append(args, cur.String())
+				cur.Reset()
+				inArg = false
+			}
+		default:
+			cur.WriteRune(r)
+			inArg = true
+		}
+	}
+	if quote != 0 {
+		return nil, errors.New("命令中的引号没有闭合")
+	}
+	if inArg {
+		args = append(args, cur.String())
+	}
+	return args, nil
+}
+
+// hookArgs 拆分模板后逐个参数替换占位符，替换后的内容始终是单个参数，不经过 shell
+func hookArgs(template string, t Todo) ([]string, error) {
+	args, err := splitCommand(template)
+	if err != nil || len(args) == 0 {
+		return nil, err
+	}
+	due := ""
+	if t.Due != nil {
+		due = t.Due.Format(time.RFC3339)
+	}
+	r := strings.NewReplacer(
+		"{text}", t.Text,
+		"{priority}", t.Priority.String(),
+		"{due}", due,
+		"{tags}", strings.Join(t.Tags, ","),
+		"{color}", t.Color,
+		"{created}", t.CreatedAt.Format(time.RFC3339),
+	)
+	for i := range args {
+		args[i] = r.Replace(args[i])
+	}
+	return args, nil
+}
+
+// runCompleteHook 在后台运行偏好设置中的完成命令，失败时记录日志并在主线程回调 onError
+func runCompleteHook(p fyne.Preferences, t Todo, onError func(error)) {
+	template := strings.TrimSpace(p.String(prefCompleteHook))
+	if template == "" {
+		return
+	}
+	args, err := hookArgs(template, t)
+	if err != nil {
+		onError(err)
+		return
+	}
+	if len(args) == 0 {
+		return
+	}
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), hookTimeout)
+		defer cancel()
+		out, err := exec.CommandContext(ctx, args[0], args[1:]...).CombinedOutput()
+		if err != nil {
+			slog.Warn("complete hook failed", "cmd", args[0], "err", err, "output", string(out))
+			fyne.Do(func() { onError(fmt.Errorf("完成命令执行失败：%w", err)) })
+			return
+		}
+		slog.Debug("complete hook done", "cmd", args[0])
+	}()
+}
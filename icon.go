@@ -0,0 +1,136 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"log/slog"
+	"os"
+	"path/filepath"
+
+	"fyne.io/fyne/v2"
+)
+
+// iconSpec 托盘图标的绘制参数，单位为像素
+type iconSpec struct {
+	Size       int // 图标边长
+	Rows       int // 复选框行数
+	CheckX     int // 复选框左边距
+	CheckTop   int // 第一个复选框的顶部位置
+	CheckSize  int // 复选框边长
+	RowGap     int // 相邻两行的垂直间隔
+	LineStartX int // 横线起点
+	LineLength int // 横线长度
+	Stroke     int // 线条粗细
+}
+
+// defaultIconSpec 极简清单图标：3个复选框 + 对应横线（左侧对齐，简洁布局）
+// 复选框位置：(6,8), (6,14), (6,20) —— 每个复选框3x3像素
+// 横线位置：从x=12开始，长度15像素，y对应复选框中间位置
+var defaultIconSpec = iconSpec{
+	Size:       32,
+	Rows:       3,
+	CheckX:     6,
+	CheckTop:   8,
+	CheckSize:  3,
+	RowGap:     6,
+	LineStartX: 12,
+	LineLength: 15,
+	Stroke:     1,
+}
+
+// iconSpecFile 记录生成 tray.png 时使用的参数，参数变化时重新生成
+func iconSpecFile() string { return iconFile + ".json" }
+
+// ensureIconFile 生成极简待办事项图标（透明背景+黑色线条）
+// 没有参数记录的 tray.png 视为用户自备的图标，不会覆盖
+func ensureIconFile() string {
+	abs, _ := filepath.Abs(iconFile)
+	spec, _ := json.Marshal(defaultIconSpec)
+	if _, err := os.Stat(iconFile); err == nil {
+		old, err := os.ReadFile(iconSpecFile())
+		if err != nil || bytes.Equal(old, spec) {
+			return abs
+		}
+	}
+
+	// 保存为PNG文件，失败时返回空路径，由调用方改用内存中的图标
+	f, err := os.Create(iconFile)
+	if err != nil {
+		slog.Warn("create icon failed", "file", iconFile, "err", err)
+		return ""
+	}
+	defer f.Close()
+	if err := png.Encode(f, drawIcon(defaultIconSpec, color.Transparent)); err != nil {
+		slog.Warn("encode png failed", "file", iconFile, "err", err)
+		return ""
+	}
+	_ = os.WriteFile(iconSpecFile(), spec, 0644)
+	slog.Debug("icon generated", "file", abs)
+	return abs
+}
+
+// trayIconResource 内存中生成的托盘图标，图标文件不可用时使用
+func trayIconResource() fyne.Resource {
+	var buf bytes.Buffer
+	_ = png.Encode(&buf, drawIcon(defaultIconSpec, color.Transparent))
+	return fyne.NewStaticResource("tray.png", buf.Bytes())
+}
+
+// scaled 按倍数放大绘制参数，线条粗细取倍数的一半以免放大后显得过粗
+func (s iconSpec) scaled(k int) iconSpec {
+	return iconSpec{
+		Size:       s.Size * k,
+		Rows:       s.Rows,
+		CheckX:     s.CheckX * k,
+		CheckTop:   s.CheckTop * k,
+		CheckSize:  s.CheckSize * k,
+		RowGap:     s.RowGap * k,
+		LineStartX: s.LineStartX * k,
+		LineLength: s.LineLength * k,
+		Stroke:     max(1, s.Stroke*k/2),
+	}
+}
+
+// drawIcon 按参数在 bg 背景上绘制黑色清单图标
+func drawIcon(s iconSpec, bg color.Color) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, s.Size, s.Size))
+	draw.Draw(img, img.Bounds(), &image.Uniform{bg}, image.Point{}, draw.Src)
+	black := &image.Uniform{color.RGBA{0, 0, 0, 255}}
+	fill := func(x, y, w, h int) {
+		draw.Draw(img, image.Rect(x, y, x+w, y+h), black, image.Point{}, draw.Src)
+	}
+
+	for i := 0; i < s.Rows; i++ {
+		x := s.CheckX
+		y := s.CheckTop + i*s.RowGap
+
+		// 绘制复选框（空心正方形）
+		fill(x, y, s.CheckSize, s.Stroke)                      // 上边
+		fill(x, y+s.CheckSize-s.Stroke, s.CheckSize, s.Stroke) // 下边
+		fill(x, y, s.Stroke, s.CheckSize)                      // 左边
+		fill(x+s.CheckSize-s.Stroke, y, s.Stroke, s.CheckSize) // 右边
+
+		// 绘制右侧横线，与复选框中间对齐
+		fill(s.LineStartX, y+s.CheckSize/2-s.Stroke/2, s.LineLength, s.Stroke)
+	}
+	return img
+}
+
+// appIconResource 返回窗口与任务栏使用的图标：优先使用偏好设置中的图片，
+// 否则生成白色背景的 256 像素版本，以便在深色任务栏上也清晰可见
+func appIconResource(p fyne.Preferences) fyne.Resource {
+	if path := p.String(prefAppIcon); path != "" {
+		res, err := fyne.LoadResourceFromPath(path)
+		if err == nil {
+			return res
+		}
+		slog.Warn("load app icon failed", "file", path, "err", err)
+	}
+	var buf bytes.Buffer
+	_ = png.Encode(&buf, drawIcon(defaultIconSpec.scaled(8), color.White))
+	return fyne.NewStaticResource("app.png", buf.Bytes())
+}
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"crypto/sha1"
+	"fmt"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+)
+
+const icsTime = "20060102T150405Z"
+
+// icsPriority iCalendar 的 PRIORITY 取值：1 最高，9 最低，0 为未设置
+var icsPriority = map[Priority]int{PriorityHigh: 1, PriorityMedium: 5, PriorityLow: 9}
+
+// exportICS 生成 iCalendar 内容：有截止时间的待办写成带提醒的 VEVENT，
+// withUndated 时其余待办写成 VTODO，否则跳过。时间一律使用 UTC
+func exportICS(p fyne.Preferences, todos []Todo, withUndated bool, now time.Time) []byte {
+	var b strings.Builder
+	line := func(s string) {
+		b.WriteString(foldICS(s))
+		b.WriteString("\r\n")
+	}
+	line("BEGIN:VCALENDAR")
+	line("VERSION:2.0")
+	line("PRODID:-//debian_mytodo//Todo//ZH")
+	for _, t := range todos {
+		if t.Done { // 已完成的不再需要日历提醒
+			continue
+		}
+		component := "VEVENT"
+		if t.Due == nil {
+			if !withUndated {
+				continue
+			}
+			component = "VTODO"
+		}
+		line("BEGIN:" + component)
+		line("UID:" + icsUID(t))
+		line("DTSTAMP:" + now.UTC().Format(icsTime))
+		line("SUMMARY:" + escapeICS(t.Text))
+		if n := icsPriority[t.Priority]; n > 0 {
+			line(fmt.Sprintf("PRIORITY:%d", n))
+		}
+		if len(t.Tags) > 0 {
+			tags := make([]string, len(t.Tags))
+			for i, tag := range t.Tags {
+				tags[i] = escapeICS(tag)
+			}
+			line("CATEGORIES:" + strings.Join(tags, ","))
+		}
+		if t.Due != nil {
+			line("DTSTART:" + t.Due.UTC().Format(icsTime))
+			line("BEGIN:VALARM")
+			line("ACTION:DISPLAY")
+			line("DESCRIPTION:" + escapeICS(t.Text))
+			line(fmt.Sprintf("TRIGGER:-PT%dM", t.remindBefore(p)))
+			line("END:VALARM")
+		}
+		line("END:" + component)
+	}
+	line("END:VCALENDAR")
+	return []byte(b.String())
+}
+
+// icsUID 取待办的 ID，修改文字后重复导出也保持不变，日历应用可据此更新而不是重复添加；
+// 没有 ID 时（不应出现，加载时已补全）退回由创建时间和文字生成
+func icsUID(t Todo) string {
+	if t.ID != "" {
+		return t.ID + "@" + appID
+	}
+	sum := sha1.Sum([]byte(t.CreatedAt.UTC().Format(time.RFC3339Nano) + "\n" + t.Text))
+	return fmt.Sprintf("%x@%s", sum[:10], appID)
+}
+
+// escapeICS 按 RFC 5545 转义文本值中的反斜杠、分号、逗号和换行，\r\n 与单独的 \r 都视为换行
+func escapeICS(s string) string {
+	return strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\r", `\n`, "\n", `\n`).Replace(s)
+}
+
+// foldICS 把超过 75 字节的行折成多行，续行以空格开头，不拆开多字节字符
+func foldICS(s string) string {
+	var b strings.Builder
+	n := 0
+	for _, r := range s {
+		size := len(string(r))
+		if n+size > 75 {
+			b.WriteString("\r\n ")
+			n = 1
+		}
+		b.WriteRune(r)
+		n += size
+	}
+	return b.String()
+}
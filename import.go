@@ -0,0 +1,187 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+)
+
+const (
+	importTimeout = 15 * time.Second
+	importMaxSize = 1 << 20 // 1 MiB
+)
+
+// fetchImport 下载待导入的内容，限制协议、大小与内容类型
+func fetchImport(ctx context.Context, rawURL string) ([]byte, string, error) {
+	u, err := url.Parse(strings.TrimSpace(rawURL))
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, "", errors.New("请输入 http:// 或 https:// 开头的链接")
+	}
+	ctx, cancel := context.WithTimeout(ctx, importTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, "", err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, "", fmt.Errorf("下载失败：%w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, "", fmt.Errorf("下载失败：%s", resp.Status)
+	}
+
+	ct, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	switch {
+	case ct == "", ct == "application/json", ct == "application/octet-stream",
+		strings.HasPrefix(ct, "text/") && ct != "text/html":
+	default:
+		return nil, "", fmt.Errorf("不支持的内容类型 %s", ct)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, importMaxSize+1))
+	if err != nil {
+		return nil, "", fmt.Errorf("下载失败：%w", err)
+	}
+	if len(data) > importMaxSize {
+		return nil, "", fmt.Errorf("文件超过 %d KB", importMaxSize>>10)
+	}
+	return data, ct, nil
+}
+
+// listMarker 匹配 Markdown 列表前缀，已勾选的 "- [x]" 单独识别
+var listMarker = regexp.MustCompile(`^\s*(?:[-*+]|\d+[.)])\s+(\[( |x|X)\]\s+)?`)
+
+// markdownHeading 匹配 Markdown 标题行："#" 后须有空白，"#标签" 不算标题
+var markdownHeading = regexp.MustCompile(`^#+(\s|$)`)
+
+// parseImport 解析导入内容：JSON 数组或导出文件，否则按行解析纯文本/Markdown
+func parseImport(data []byte, contentType string) ([]Todo, error) {
+	trimmed := bytes.TrimSpace(data)
+	if contentType == "application/json" || bytes.HasPrefix(trimmed, []byte("[")) || bytes.HasPrefix(trimmed, []byte("{")) {
+		return parseImportJSON(trimmed)
+	}
+
+	var todos []Todo
+	now := time.Now()
+	sc := bufio.NewScanner(bytes.NewReader(data))
+	for sc.Scan() {
+		line, done, item := sc.Text(), false, false
+		if m := listMarker.FindStringSubmatch(line); m != nil {
+			line, done, item = line[len(m[0]):], strings.EqualFold(m[2], "x"), true
+		}
+		line = strings.TrimSpace(line)
+		if line == "" || !item && markdownHeading.MatchString(line) {
+			continue // 跳过空行与 Markdown 标题，以 #标签 开头的行照常导入
+		}
+		t := Todo{Text: line}
+		if item {
+			t = parseMarkdownItem(line, now)
+		}
+		// 已勾选的事项导入为已完成，是否保留由导入方决定
+		t.Done = done
+		todos = append(todos, t)
+	}
+	return todos, sc.Err()
+}
+
+// parseMarkdownItem 解析 Markdown 列表项中的行内标记，与 exportMarkdown 写入的方式对应；
+// 行尾 @ 开头且不是日期的词视为情境
+func parseMarkdownItem(line string, now time.Time) Todo {
+	t := parseTokens(line, now)
+	if i := strings.LastIndex(t.Text, " @"); i >= 0 && i+2 < len(t.Text) && !strings.Contains(t.Text[i+2:], " ") {
+		t.Text, t.Context = t.Text[:i], t.Text[i+1:]
+	}
+	if t.Text == "" {
+		return Todo{Text: line} // 只有标记时保留原文，不丢内容
+	}
+	return t
+}
+
+func parseImportJSON(data []byte) ([]Todo, error) {
+	var todos []Todo
+	if data[0] == '[' {
+		if err := json.Unmarshal(data, &todos); err != nil {
+			return nil, fmt.Errorf("JSON 解析失败：%w", err)
+		}
+		return todos, nil
+	}
+	var f exportFile
+	if err := json.Unmarshal(data, &f); err != nil {
+		return nil, fmt.Errorf("JSON 解析失败：%w", err)
+	}
+	if f.SchemaVersion > schemaVersion {
+		return nil, fmt.Errorf("文件格式版本 %d 高于当前支持的 %d，请升级程序", f.SchemaVersion, schemaVersion)
+	}
+	if err := json.Unmarshal(f.Todos, &todos); err != nil {
+		return nil, fmt.Errorf("JSON 解析失败：%w", err)
+	}
+	return todos, nil
+}
+
+// 导入时遇到文字与现有待办相同的条目的处理方式
+const (
+	importSkip  = "skip"  // 跳过
+	importMerge = "merge" // 把新条目的标签、截止时间等补到现有条目上
+	importAll   = "all"   // 照常添加
+)
+
+var importModes = []struct{ mode, label string }{
+	{importSkip, "跳过重复的"},
+	{importMerge, "合并到已有条目"},
+	{importAll, "全部添加"},
+}
+
+// showURLImportDialog 输入链接并在后台下载解析，成功后在主线程调用 onImport
+func showURLImportDialog(win fyne.Window, onImport func(todos []Todo, mode string)) {
+	entry := widget.NewEntry()
+	entry.SetPlaceHolder("https://example.com/checklist.md")
+	var labels []string
+	for _, m := range importModes {
+		labels = append(labels, m.label)
+	}
+	mode := widget.NewRadioGroup(labels, nil)
+	mode.Required = true
+	mode.SetSelected(labels[0])
+	dialog.ShowForm("从链接导入", "导入", "取消", []*widget.FormItem{
+		widget.NewFormItem("链接", entry),
+		widget.NewFormItem("已有的条目", mode),
+	}, func(ok bool) {
+		if !ok {
+			return
+		}
+		// 控件只在主线程读取，下载前先取出链接
+		link := entry.Text
+		showTemporaryPopUp(win.Canvas(), "正在下载…", 1)
+		go func() {
+			data, ct, err := fetchImport(context.Background(), link)
+			var todos []Todo
+			if err == nil {
+				todos, err = parseImport(data, ct)
+			}
+			fyne.Do(func() {
+				if err != nil {
+					showTemporaryPopUp(win.Canvas(), err.Error(), 3)
+					return
+				}
+				onImport(todos, importModes[max(slices.Index(labels, mode.Selected), 0)].mode)
+			})
+		}()
+	}, win)
+}
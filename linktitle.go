@@ -0,0 +1,84 @@
+package main
+
+import (
+	"context"
+	"html"
+	"io"
+	"log/slog"
+	"net/http"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"fyne.io/fyne/v2"
+)
+
+const (
+	linkTitleTimeout = 5 * time.Second
+	linkTitleMaxSize = 64 << 10 // 只读取页面开头，<title> 通常在其中
+	linkTitleMaxLen  = 60
+)
+
+var (
+	linkPattern  = regexp.MustCompile(`https?://[^\s<>"]+`)
+	titlePattern = regexp.MustCompile(`(?is)<title[^>]*>(.*?)</title>`)
+)
+
+// linkTitles 已获取的页面标题，获取失败的记为空串，本次运行中不再重试
+var linkTitles = struct {
+	sync.Mutex
+	titles map[string]string
+}{titles: map[string]string{}}
+
+// fetchLinkTitle 下载页面开头并取出 <title>，失败时返回空串
+func fetchLinkTitle(rawURL string) string {
+	ctx, cancel := context.WithTimeout(context.Background(), linkTitleTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return ""
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		slog.Debug("fetch link title failed", "url", rawURL, "err", err)
+		return ""
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || !strings.Contains(resp.Header.Get("Content-Type"), "html") {
+		return ""
+	}
+	data, _ := io.ReadAll(io.LimitReader(resp.Body, linkTitleMaxSize))
+	m := titlePattern.FindSubmatch(data)
+	if m == nil {
+		return ""
+	}
+	return truncateRunes(normalizeSpace(html.UnescapeString(string(m[1]))), linkTitleMaxLen)
+}
+
+// linkDisplay 把文字中的链接替换为已获取的页面标题，尚未获取的在后台获取，
+// 完成后在主线程调用 onFetched；没有标题的保留原链接
+func linkDisplay(text string, onFetched func()) string {
+	linkTitles.Lock()
+	defer linkTitles.Unlock()
+	return linkPattern.ReplaceAllStringFunc(text, func(link string) string {
+		title, ok := linkTitles.titles[link]
+		if !ok {
+			linkTitles.titles[link] = "" // 占位，避免重复获取
+			go func() {
+				title := fetchLinkTitle(link)
+				if title == "" {
+					return
+				}
+				linkTitles.Lock()
+				linkTitles.titles[link] = title
+				linkTitles.Unlock()
+				fyne.Do(onFetched)
+			}()
+		}
+		if title == "" {
+			return link
+		}
+		return "🔗" + title
+	})
+}
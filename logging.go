@@ -0,0 +1,33 @@
+package main
+
+import (
+	"io"
+	"log/slog"
+	"os"
+)
+
+const logFile = "todo.log"
+
+// setupLogging 配置全局日志：默认只输出警告和错误，verbose 时输出加载、保存、通知、托盘等调试信息；
+// toFile 时同时追加写入数据目录下的 todo.log。返回的函数在退出时关闭日志文件
+func setupLogging(verbose, toFile bool) func() {
+	level := slog.LevelWarn
+	if verbose {
+		level = slog.LevelDebug
+	}
+	var w io.Writer = os.Stderr
+	closeFn := func() {}
+	if toFile {
+		f, err := os.OpenFile(logFile, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+		if err != nil {
+			slog.Warn("open log file failed", "file", logFile, "err", err)
+		} else {
+			w = io.MultiWriter(os.Stderr, f)
+			closeFn = func() { f.Close() }
+		}
+	}
+	slog.SetDefault(slog.New(slog.NewTextHandler(w, &slog.HandlerOptions{Level: level})))
+	// 标准库 log（log.Fatal 及 Fyne 内部错误）按错误级别输出，安静模式下也不会被过滤
+	slog.SetLogLoggerLevel(slog.LevelError)
+	return closeFn
+}
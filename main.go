@@ -1,229 +1,1714 @@
 package main
 
 import (
-	"encoding/json"
-	"image"
-	"image/color"
-	"image/draw"
-	"image/png"
+	"errors"
+	"flag"
+	"fmt"
 	"log"
+	"log/slog"
 	"os"
-	"path/filepath"
+	"os/signal"
+	"slices"
+	"strconv"
+	"strings"
+	"syscall"
 	"time"
-	"unicode/utf8"
 
 	"fyne.io/fyne/v2"
 	"fyne.io/fyne/v2/app"
 	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
 	"fyne.io/fyne/v2/driver/desktop"
 	"fyne.io/fyne/v2/layout"
+	"fyne.io/fyne/v2/theme"
 	"fyne.io/fyne/v2/widget"
 )
 
 const (
 	iconFile = "tray.png"
-	dataFile = "todo.json"
 	appID    = "io.github.dylan.todo.tray"
-	maxLen   = 50 // 每条最多50汉字
+	winTitle = "待办事项" // 主窗口标题，wmctrl 按标题查找窗口
 )
 
-type Todo struct {
-	Text string `json:"text"`
+func showTemporaryPopUp(c fyne.Canvas, text string, seconds float64) {
+	label := widget.NewLabel(text)
+	label.Alignment = fyne.TextAlignCenter
+
+	pop := widget.NewPopUp(container.NewCenter(label), c)
+	pop.Show()
+
+	go func() {
+		time.Sleep(time.Duration(seconds * float64(time.Second)))
+		fyne.Do(func() {
+			pop.Hide()
+		})
+	}()
 }
 
-func loadTodos() ([]Todo, error) {
-	if _, err := os.Stat(dataFile); os.IsNotExist(err) {
-		return []Todo{}, nil
+func main() {
+	format := flag.String("format", "json", "存储格式：json、gzip（压缩的 json，保存为 "+gzipFile+"）或 txt（txt 每行一条，不保存其它字段）")
+	var verbose bool
+	flag.BoolVar(&verbose, "v", false, "输出调试日志（加载、保存、通知、托盘等）")
+	flag.BoolVar(&verbose, "debug", false, "同 -v")
+	logToFile := flag.Bool("logfile", false, "同时将日志写入数据目录下的 "+logFile)
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "用法：%s [选项] [check [-json] [-maxlen N]]\n", os.Args[0])
+		flag.PrintDefaults()
 	}
-	data, err := os.ReadFile(dataFile)
-	if err != nil {
-		return nil, err
+	flag.Parse()
+	defer setupLogging(verbose, *logToFile)()
+	if err := useStore(*format); err != nil {
+		log.Fatal(err)
 	}
-	var todos []Todo
-	return todos, json.Unmarshal(data, &todos)
-}
 
-func saveTodos(todos []Todo) {
-	data, _ := json.MarshalIndent(todos, "", "  ")
-	_ = os.WriteFile(dataFile, data, 0644)
-}
+	// 创建应用只读取偏好设置，不打开窗口，check 也借此使用设置中的最大字数
+	a := app.NewWithID(appID)
+	if flag.Arg(0) == "check" {
+		os.Exit(runCheck(a.Preferences(), *format, flag.Args()[1:], os.Stdout))
+	}
 
-// ensureIconFile 生成极简待办事项图标（透明背景+黑色线条）
-func ensureIconFile() string {
-	if _, err := os.Stat(iconFile); err == nil {
-		abs, _ := filepath.Abs(iconFile)
-		return abs
+	iconPath := ensureIconFile()
+
+	todos, err := loadTodos()
+	loadTagColors()
+	unreadable := errors.Is(err, errUnreadable)
+	corrupted := err == errChecksum || unreadable
+	if err != nil && !corrupted {
+		log.Fatal(err)
 	}
 
-	// 创建32x32透明背景图像
-	img := image.NewRGBA(image.Rect(0, 0, 32, 32))
-	draw.Draw(img, img.Bounds(), &image.Uniform{color.RGBA{0, 0, 0, 0}}, image.Point{}, draw.Src)
-	black := color.RGBA{0, 0, 0, 255}
+	listBox := container.NewVBox()
+	input := newSoftEntry()
+	var refreshList, rebuildTray, applyIcon, layoutMain, rescheduleReminders, focusOnOpen, stopControl, stopHTTP func()
+	applyPrefs := func() {
+		setSaveMode(a.Preferences().StringWithFallback(prefSaveMode, saveImmediate))
+		applyTheme(a)
+		hint := "回车确认"
+		soft := a.Preferences().Bool(prefSoftBreak)
+		if soft {
+			hint = "回车确认，Shift+回车换行"
+		}
+		input.setSoftBreak(soft)
+		if lines := composerLines(a.Preferences()); lines > 0 {
+			hint = "回车换行，Shift+回车或点击添加确认"
+			input.setComposer(lines)
+		} else {
+			input.setComposer(0)
+		}
+		input.SetPlaceHolder(fmt.Sprintf("新增待办事项，%s（最多%d字）", hint, maxTextLen(a.Preferences())))
+		if rebuildTray != nil {
+			rebuildTray()
+		}
+		if applyIcon != nil {
+			applyIcon()
+		}
+		if layoutMain != nil {
+			layoutMain()
+		}
+		if refreshList != nil {
+			refreshList()
+		}
+	}
+	applyPrefs()
 
-	// 绘制极简清单图标：3个复选框 + 对应横线（左侧对齐，简洁布局）
-	// 复选框位置：(6,8), (6,14), (6,20) —— 每个复选框3x3像素
-	// 横线位置：从x=12开始，长度15像素，y对应复选框中间位置
-	checkSize := 3 // 复选框边长（像素）
-	lineStartX := 12
-	lineLength := 15
-	lineYOffsets := []int{9, 15, 21} // 横线垂直位置（对应复选框中间）
+	win := a.NewWindow(winTitle)
+	applyIcon = func() {
+		icon := appIconResource(a.Preferences())
+		a.SetIcon(icon)
+		win.SetIcon(icon)
+	}
+	applyIcon()
+	win.Resize(fyne.NewSize(360, 440))
+	win.SetFixedSize(false)
+
+	// 滚动位置：隐藏窗口与退出时保存，刷新列表或重新打开时恢复
+	listScroll := container.NewVScroll(container.NewBorder(nil, nil, nil, layout.NewSpacer(), listBox))
+	scrollY := float32(a.Preferences().Float(prefScrollOffset))
+	listScroll.OnScrolled = func(p fyne.Position) {
+		scrollY = p.Y
+	}
+	restoreScroll := func() {
+		// 条目减少后旧位置可能超出范围，收拢到最近的有效位置
+		y := min(scrollY, listScroll.Content.MinSize().Height-listScroll.Size().Height)
+		listScroll.ScrollToOffset(fyne.NewPos(0, max(y, 0)))
+	}
+	saveScroll := func() {
+		a.Preferences().SetFloat(prefScrollOffset, float64(scrollY))
+	}
+	a.Lifecycle().SetOnStopped(func() {
+		saveScroll()
+		flushSave()
+		exportOnQuit(a.Preferences(), todos)
+		flushNotes()
+		if stopControl != nil {
+			stopControl()
+		}
+		if stopHTTP != nil {
+			stopHTTP()
+		}
+	})
 
-	for i := 0; i < 3; i++ {
-		x := 6
-		y := 8 + i*6 // 每个复选框垂直间隔6像素
+	// 收到终止信号时先写入未保存的修改再退出
+	sig := make(chan os.Signal, 1)
+	signal.Notify(sig, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-sig
+		flushSave()
+		fyne.Do(a.Quit)
+	}()
 
-		// 绘制复选框（空心正方形，1像素边框）
-		// 上边
-		for dx := 0; dx < checkSize; dx++ {
-			img.Set(x+dx, y, black)
+	// showMainWindow 显示并激活主窗口。窗口已显示但不在前台时（最小化、在其它工作区或被遮挡），
+	// 先隐藏再显示，让窗口管理器重新映射窗口，从而恢复最小化并移到当前工作区
+	winVisible, foreground := false, false
+	a.Lifecycle().SetOnEnteredForeground(func() { foreground = true })
+	a.Lifecycle().SetOnExitedForeground(func() { foreground = false })
+	showMainWindow := func() {
+		if winVisible && !foreground {
+			win.Hide()
 		}
-		// 下边
-		for dx := 0; dx < checkSize; dx++ {
-			img.Set(x+dx, y+checkSize-1, black)
+		win.Show()
+		win.RequestFocus()
+		winVisible = true
+		if a.Preferences().Bool(prefAllWorkspaces) {
+			setSticky(winTitle, true)
 		}
-		// 左边
-		for dy := 0; dy < checkSize; dy++ {
-			img.Set(x, y+dy, black)
+		restoreScroll()
+		if focusOnOpen != nil {
+			focusOnOpen()
 		}
-		// 右边
-		for dy := 0; dy < checkSize; dy++ {
-			img.Set(x+checkSize-1, y+dy, black)
+	}
+
+	win.SetCloseIntercept(func() {
+		saveScroll()
+		win.Hide()
+		winVisible = false
+	})
+
+	// 颜色筛选，未选择时显示全部
+	colorFilter := widget.NewSelect(colorFilterOptions(), nil)
+	colorFilter.SetSelectedIndex(0)
+	viewFilter := widget.NewSelect(viewOptions, nil)
+	viewFilter.SetSelectedIndex(0)
+	contextFilter := widget.NewSelect(contextFilterOptions(todos), nil)
+	contextFilter.SetSelected(contextAll)
+	status := newStatusBar(viewFilter.SetSelected)
+	// filterChips 列出正在生效的筛选条件，点击移除该条件
+	filterChips := container.NewHBox()
+	filterChips.Hide()
+
+	// copyText 复制文字到剪贴板并提示，"复制" 按钮与 Ctrl+C 共用
+	copyText := func(text string) {
+		text, variant := copyVariant(a.Preferences().String(prefCopyMode), text, time.Now())
+		a.Clipboard().SetContent(text)
+		if a.Preferences().BoolWithFallback(prefCopyPopup, true) {
+			msg := "已复制到剪贴板"
+			if variant != "" {
+				msg += "（" + variant + "）"
+			}
+			showTemporaryPopUp(win.Canvas(), msg, 2)
 		}
+	}
+
+	// rowOf 记录行内可聚焦控件对应的待办 ID，用于键盘操作
+	rowOf := map[fyne.Focusable]string{}
+	// checks 按显示顺序保存各行的复选框，用于连续完成时移动焦点
+	var checks []*widget.Check
 
-		// 绘制右侧横线（1像素高度）
-		lineY := lineYOffsets[i]
-		for dx := 0; dx < lineLength; dx++ {
-			img.Set(lineStartX+dx, lineY, black)
+	// hist 撤销/重做栈，所有修改列表的操作都先记录快照
+	var hist history
+
+	// indexOf 按 ID 查找待办的当前下标，找不到时返回 -1
+	indexOf := func(id string) int {
+		return slices.IndexFunc(todos, func(t Todo) bool { return t.ID == id })
+	}
+
+	// updateAt 修改指定 ID 的待办并记录修改时间，label 用于撤销提示；
+	// 按 ID 查找，列表在回调之前变化也不会改错条目
+	updateAt := func(id string, label string, change func(t *Todo)) {
+		index := indexOf(id)
+		if index < 0 {
+			return
+		}
+		hist.record(label, todos)
+		from := todos[index].Text
+		change(&todos[index])
+		todos[index].UpdatedAt = time.Now()
+		entry := todoChange(label, todos[index])
+		if from != entry.Text {
+			entry.From = from
 		}
+		logChanges(a.Preferences(), entry)
+		saveTodos(todos)
+		refreshList()
 	}
 
-	// 保存为PNG文件
-	f, err := os.Create(iconFile)
-	if err != nil {
-		log.Fatal("create icon failed:", err)
+	// 分栏模式的详情面板，保存时按 ID 写回
+	pane := newDetailPane(a.Preferences(), func(id string, change func(*Todo)) {
+		updateAt(id, "编辑详情", change)
+	})
+	body := newAdaptiveSplit(listScroll, pane.content)
+
+	// finishAt 完成（移除）指定 ID 的待办，outcome 为结果备注，可以为空
+	finishAt := func(id, outcome string) {
+		index := indexOf(id)
+		if index < 0 {
+			return
+		}
+		if todos[index].Done {
+			return
+		}
+		hist.record("完成", todos)
+		if a.Preferences().Bool(prefCompleteSound) {
+			playChime()
+		}
+		todos[index].Outcome = outcome
+		runCompleteHook(a.Preferences(), todos[index], func(err error) {
+			showTemporaryPopUp(win.Canvas(), err.Error(), 3)
+		})
+		done := todos[index]
+		// 开启"完成后保留"时只打勾并记录完成时间，否则从列表中移除
+		if keepDone(a.Preferences()) {
+			now := time.Now()
+			todos[index].Done, todos[index].CompletedAt, todos[index].UpdatedAt = true, &now, now
+		} else {
+			todos = append(todos[:index], todos[index+1:]...)
+		}
+		entry := todoChange("完成", done)
+		entry.Note = outcome
+		logChanges(a.Preferences(), entry)
+		saveTodos(todos)
+		refreshList()
+
+		// 提示因这一条完成而解除阻塞的待办
+		var unblocked []string
+		for _, t := range todos {
+			if t.DependsOn == done.ID {
+				unblocked = append(unblocked, t.Text)
+			}
+		}
+		if len(unblocked) > 0 {
+			showTemporaryPopUp(win.Canvas(), "可以开始了："+strings.Join(unblocked, "、"), 3)
+		}
 	}
-	defer f.Close()
-	if err := png.Encode(f, img); err != nil {
-		log.Fatal("encode png failed:", err)
+	// completeAt 完成指定 ID 的待办；开启结果备注且主窗口可见时先询问，
+	// 跳过或按 Esc 照常完成
+	completeAt := func(id string) {
+		if !a.Preferences().Bool(prefOutcomePrompt) || !winVisible {
+			finishAt(id, "")
+			return
+		}
+		note := widget.NewEntry()
+		note.SetPlaceHolder("结果如何？可留空")
+		d := dialog.NewForm("完成", "记录", "跳过", []*widget.FormItem{
+			widget.NewFormItem("结果", note),
+		}, func(ok bool) {
+			outcome := ""
+			if ok {
+				outcome = strings.TrimSpace(note.Text)
+			}
+			finishAt(id, outcome)
+		}, win)
+		note.OnSubmitted = func(string) { d.Submit() }
+		d.Resize(fyne.NewSize(win.Canvas().Size().Width*0.8, 0))
+		d.Show()
+		win.Canvas().Focus(note)
 	}
 
-	abs, _ := filepath.Abs(iconFile)
-	return abs
-}
+	// deleteAt 删除一条但不视为完成，不运行完成时的命令，可撤销
+	deleteAt := func(id string) {
+		index := indexOf(id)
+		if index < 0 {
+			return
+		}
+		hist.record("删除", todos)
+		removed := todos[index]
+		todos = append(todos[:index], todos[index+1:]...)
+		logChanges(a.Preferences(), todoChange("删除", removed))
+		saveTodos(todos)
+		refreshList()
+		showTemporaryPopUp(win.Canvas(), "已删除，Ctrl+Z 撤销", 2)
+	}
 
-func showTemporaryPopUp(c fyne.Canvas, text string, seconds float64) {
-	label := widget.NewLabel(text)
-	label.Alignment = fyne.TextAlignCenter
+	// splitAt 把 id 拆成按 parts 排列的多条，放在原来的位置；
+	// 每条沿用原待办的优先级、截止时间、标签等，只有文字和 ID 不同
+	splitAt := func(id string, parts []string) {
+		index := indexOf(id)
+		if index < 0 {
+			return
+		}
+		if limit := maxItemCount(a.Preferences()); limit > 0 && len(todos)+len(parts)-1 > limit {
+			showTemporaryPopUp(win.Canvas(), fmt.Sprintf("拆分后超过 %d 条上限", limit), 2)
+			return
+		}
+		now := time.Now()
+		var split []Todo
+		for _, part := range parts {
+			text, err := checkTodoText(a.Preferences(), part)
+			if err != nil {
+				showTemporaryPopUp(win.Canvas(), err.Error(), 2)
+				return
+			}
+			t := cloneTodos(todos[index : index+1])[0]
+			t.ID, t.Text, t.CreatedAt, t.UpdatedAt = newID(), text, now, now
+			split = append(split, t)
+		}
+		hist.record("拆分", todos)
+		changes := []changeEntry{todoChange("拆分", todos[index])}
+		for _, t := range split {
+			changes = append(changes, todoChange("添加", t))
+		}
+		todos = slices.Replace(todos, index, index+1, split...)
+		logChanges(a.Preferences(), changes...)
+		saveTodos(todos)
+		refreshList()
+		showTemporaryPopUp(win.Canvas(), fmt.Sprintf("已拆分为 %d 条", len(split)), 2)
+	}
 
-	pop := widget.NewPopUp(container.NewCenter(label), c)
-	pop.Show()
+	// lastAdded 最近一次添加时的文字，只保存在内存中，供"重复上一条"使用
+	lastAdded := ""
+	// insertText 校验并在 at 处插入一条待办，source 为创建途径；
+	// change 不为空时在保存前修改新待办，用于高级添加栏中填写的字段
+	insertText := func(text, source string, at int, change func(*Todo)) error {
+		todo, err := newTodo(a.Preferences(), text, time.Now())
+		if err != nil {
+			return err
+		}
+		todo.Source = source
+		if change != nil {
+			change(&todo)
+		}
+		if limit := maxItemCount(a.Preferences()); limit > 0 && len(todos) >= limit {
+			return fmt.Errorf("已达到 %d 条上限，请先完成一些待办", limit)
+		}
+		hist.record("添加", todos)
+		todos = slices.Insert(todos, min(max(at, 0), len(todos)), todo)
+		lastAdded = text
+		logChanges(a.Preferences(), todoChange("添加", todo))
+		saveTodos(todos)
+		refreshList()
+		return nil
+	}
+	// addText 追加到末尾，主输入框、快速添加、控制接口与示例共用；
+	// 校验都在 newTodo 中进行，空输入返回 errEmptyTodo 由调用方忽略
+	addText := func(text, source string) error {
+		return insertText(text, source, len(todos), nil)
+	}
 
-	go func() {
-		time.Sleep(time.Duration(seconds * float64(time.Second)))
-		fyne.Do(func() {
-			pop.Hide()
+	// insertNear 在显示位置紧挨 id 的上方或下方插入新待办。列表按保存顺序显示，
+	// 筛选不改变相对顺序，倒序显示时保存顺序与显示相反；
+	// 对话框打开期间 id 已被删除时追加到末尾，不丢弃输入
+	insertNear := func(id string, below bool) {
+		entry := widget.NewEntry()
+		entry.Validator = func(text string) error {
+			_, err := newTodo(a.Preferences(), text, time.Now())
+			return err
+		}
+		title := "在上方插入"
+		if below {
+			title = "在下方插入"
+		}
+		submit := func() error {
+			at := indexOf(id)
+			switch {
+			case at < 0:
+				at = len(todos)
+			case below != a.Preferences().Bool(prefNewestFirst):
+				at++
+			}
+			if err := insertText(entry.Text, sourceUI, at, nil); err != errEmptyTodo {
+				return err
+			}
+			return nil
+		}
+		var d dialog.Dialog
+		entry.OnSubmitted = func(string) {
+			if err := submit(); err != nil {
+				entry.SetValidationError(err)
+				return
+			}
+			d.Hide()
+		}
+		// 点击按钮时对话框已经关闭，出错时改用弹出提示
+		d = dialog.NewCustomConfirm(title, "添加", "取消", entry, func(ok bool) {
+			if !ok {
+				return
+			}
+			if err := submit(); err != nil {
+				showTemporaryPopUp(win.Canvas(), err.Error(), 2)
+			}
+		}, win)
+		d.Resize(fyne.NewSize(320, d.MinSize().Height))
+		d.Show()
+		win.Canvas().Focus(entry)
+	}
+
+	// 专注模式：只显示最优先的一条，完成后自动切到下一条
+	focusMode := a.Preferences().Bool(prefFocusMode)
+	var mainView fyne.CanvasObject
+	var showView func()
+	focus := newFocusView(func() {
+		if i := focusIndex(todos); i >= 0 {
+			completeAt(todos[i].ID)
+		}
+	}, func() {
+		focusMode = false
+		a.Preferences().SetBool(prefFocusMode, false)
+		showView()
+	})
+	showView = func() {
+		if focusMode {
+			win.SetContent(focus.content)
+		} else {
+			win.SetContent(mainView)
+		}
+	}
+	enterFocus := func() {
+		focusMode = true
+		a.Preferences().SetBool(prefFocusMode, true)
+		showView()
+	}
+	focusBtn := widget.NewButtonWithIcon("专注", theme.VisibilityIcon(), enterFocus)
+
+	// focusOnOpen 打开窗口后默认聚焦输入框以便立即输入，也可设为聚焦列表第一行
+	focusOnOpen = func() {
+		if focusMode {
+			return
+		}
+		if a.Preferences().Bool(prefFocusList) && len(checks) > 0 {
+			win.Canvas().Focus(checks[0])
+			return
+		}
+		win.Canvas().Focus(input)
+	}
+
+	// keyActions 快捷键登记表，帮助窗口与窗口快捷键都由它生成，在各操作定义之后填写
+	var keyActions []keyAction
+
+	// 没有控件获得焦点时：F1 或 ? 显示快捷键帮助，Esc 关闭帮助；
+	// 专注模式下空格或回车直接完成当前一条
+	win.Canvas().SetOnTypedRune(func(r rune) {
+		if r == '?' || r == '？' {
+			showShortcutHelp(win, keyActions)
+		}
+	})
+	win.Canvas().SetOnTypedKey(func(key *fyne.KeyEvent) {
+		switch key.Name {
+		case fyne.KeyF1:
+			showShortcutHelp(win, keyActions)
+			return
+		case fyne.KeyEscape:
+			hideShortcutHelp()
+			return
+		}
+		if !focusMode || (key.Name != fyne.KeySpace && key.Name != fyne.KeyReturn && key.Name != fyne.KeyEnter) {
+			return
+		}
+		if i := focusIndex(todos); i >= 0 {
+			completeAt(todos[i].ID)
+		}
+	})
+	focusBtn.Importance = widget.LowImportance
+	reorderBtn := widget.NewButtonWithIcon("", theme.ListIcon(), func() {
+		showReorderDialog(win, todos, func(ids []string) {
+			hist.record("调整顺序", todos)
+			todos = applyOrder(todos, ids)
+			logChanges(a.Preferences(), changeEntry{Action: "调整顺序"})
+			saveTodos(todos)
+			refreshList()
 		})
-	}()
-}
+	})
+	reorderBtn.Importance = widget.LowImportance
 
-func main() {
-	a := app.NewWithID(appID)
-	iconPath := ensureIconFile()
+	// importTodos 按 mode 处理与现有待办文字相同的条目，返回添加、跳过与合并的条数
+	importTodos := func(items []Todo, mode string) (added, skipped, merged int) {
+		now := time.Now()
+		limit := maxItemCount(a.Preferences())
+		before := cloneTodos(todos)
+		var changes []changeEntry
+		for _, t := range items {
+			text, err := checkTodoText(a.Preferences(), t.Text)
+			if err != nil {
+				skipped++
+				continue
+			}
+			t.Text, t.Source = text, sourceImport
+			if a.Preferences().Bool(prefNormalizeTags) {
+				t.Tags = normalizeTags(t.Tags)
+			}
+			// 未开启"完成后保留"时已完成的条目无处显示，与以前一样跳过
+			if t.Done && !keepDone(a.Preferences()) {
+				skipped++
+				continue
+			}
+			// 只与完成状态相同的比较：已完成的同名条目不会挡住新的未完成条目
+			if mode != importAll {
+				if i := slices.IndexFunc(todos, func(e Todo) bool { return e.Text == text && e.Done == t.Done }); i >= 0 {
+					if mode == importMerge && mergeTodo(&todos[i], t) {
+						todos[i].UpdatedAt = now
+						changes = append(changes, todoChange("导入合并", todos[i]))
+						merged++
+					} else {
+						skipped++
+					}
+					continue
+				}
+			}
+			if limit > 0 && len(todos) >= limit {
+				skipped++
+				continue
+			}
+			if t.ID == "" || slices.ContainsFunc(todos, func(e Todo) bool { return e.ID == t.ID }) {
+				t.ID = newID()
+			}
+			if t.CreatedAt.IsZero() {
+				t.CreatedAt = now
+			}
+			if t.UpdatedAt.IsZero() {
+				t.UpdatedAt = t.CreatedAt
+			}
+			todos = append(todos, t)
+			changes = append(changes, todoChange("导入", t))
+			added++
+		}
+		if added > 0 || merged > 0 {
+			hist.record("导入", before)
+			logChanges(a.Preferences(), changes...)
+			saveTodos(todos)
+			refreshList()
+		}
+		return added, skipped, merged
+	}
 
-	todos, err := loadTodos()
-	if err != nil {
-		log.Fatal(err)
+	// setZoom 调整界面缩放并保存，percent 超出范围时收拢；Ctrl+= / Ctrl+- / Ctrl+0 与列表菜单共用
+	setZoom := func(percent int) {
+		percent = min(max(percent, minZoom), maxZoom)
+		a.Preferences().SetInt(prefZoom, percent)
+		applyTheme(a)
+		showTemporaryPopUp(win.Canvas(), fmt.Sprintf("缩放 %d%%", percent), 1)
+	}
+	zoomMenuItem := func() *fyne.MenuItem {
+		zoom := zoomPercent(a.Preferences())
+		item := fyne.NewMenuItem(fmt.Sprintf("缩放（%d%%）", zoom), nil)
+		item.ChildMenu = fyne.NewMenu("",
+			fyne.NewMenuItem("放大", func() { setZoom(zoom + zoomStep) }),
+			fyne.NewMenuItem("缩小", func() { setZoom(zoom - zoomStep) }),
+			fyne.NewMenuItem("还原", func() { setZoom(defaultZoom) }),
+		)
+		return item
 	}
 
-	listBox := container.NewVBox()
-	input := widget.NewEntry()
-	input.SetPlaceHolder("新增待办事项，回车确认（最多50字）")
+	// renameAllTags 按 renames 修改全部待办的标签，作为一步记录以便撤销
+	renameAllTags := func(label string, renames map[string]string) {
+		hist.record(label, todos)
+		var changes []changeEntry
+		now := time.Now()
+		for i := range todos {
+			tags := renameTags(todos[i].Tags, renames)
+			if !slices.Equal(tags, todos[i].Tags) {
+				todos[i].Tags, todos[i].UpdatedAt = tags, now
+				changes = append(changes, todoChange("修改标签", todos[i]))
+			}
+		}
+		logChanges(a.Preferences(), changes...)
+		saveTodos(todos)
+		refreshList()
+	}
 
-	win := a.NewWindow("待办事项")
-	win.Resize(fyne.NewSize(360, 440))
-	win.SetFixedSize(false)
-	win.SetCloseIntercept(func() {
-		win.Hide()
+	// runReview 打开"需要回顾"对话框；定时触发且窗口未显示时另发通知，
+	// 对话框留在窗口中，下次打开时处理。manual 为从菜单打开，没有需要回顾的待办时提示
+	runReview := func(manual bool) {
+		days := reviewDays(a.Preferences())
+		now := time.Now()
+		items := staleTodos(todos, days, now)
+		if len(items) == 0 {
+			if manual {
+				showTemporaryPopUp(win.Canvas(), fmt.Sprintf("没有超过 %d 天未改动的待办", days), 2)
+			}
+			return
+		}
+		if !winVisible {
+			a.SendNotification(fyne.NewNotification("需要回顾", fmt.Sprintf("有 %d 条待办超过 %d 天没有改动", len(items), days)))
+		}
+		showReviewDialog(win, items, now, func(id string) {
+			updateAt(id, "回顾保留", func(*Todo) {})
+		}, completeAt, deleteAt)
+	}
+
+	// 列表菜单：导入导出等整体操作
+	listMenuItems := func() []*fyne.MenuItem {
+		return []*fyne.MenuItem{
+			fyne.NewMenuItem("从链接导入…", func() {
+				showURLImportDialog(win, func(items []Todo, mode string) {
+					added, skipped, merged := importTodos(items, mode)
+					msg := fmt.Sprintf("已导入 %d 条", added)
+					if merged > 0 {
+						msg += fmt.Sprintf("，合并 %d 条", merged)
+					}
+					if skipped > 0 {
+						msg += fmt.Sprintf("，跳过 %d 条", skipped)
+					}
+					showTemporaryPopUp(win.Canvas(), msg, 3)
+				})
+			}),
+			fyne.NewMenuItem("导出 JSON…", func() {
+				now := time.Now()
+				showExportDialog(win, exportName(a.Preferences(), "json", now), exportJSON(todos, tagColors, now))
+			}),
+			fyne.NewMenuItem("导出 Markdown 表格…", func() { showMarkdownTableDialog(win, a.Preferences(), todos) }),
+			fyne.NewMenuItem("导出日历 (.ics)…", func() {
+				export := func(withUndated bool) {
+					now := time.Now()
+					showExportDialog(win, exportName(a.Preferences(), "ics", now), exportICS(a.Preferences(), todos, withUndated, now))
+				}
+				undated := 0
+				for _, t := range todos {
+					if t.Due == nil {
+						undated++
+					}
+				}
+				if undated == 0 {
+					export(false)
+					return
+				}
+				d := dialog.NewConfirm("导出日历", fmt.Sprintf("有 %d 条待办没有截止时间，是否作为无日期任务一并导出？", undated), export, win)
+				d.SetConfirmText("一并导出")
+				d.SetDismissText("跳过")
+				d.Show()
+			}),
+			fyne.NewMenuItem("管理标签…", func() {
+				showTagManager(win, todos, func(renames map[string]string) {
+					if len(renames) > 0 {
+						renameAllTags("管理标签", renames)
+						return
+					}
+					refreshList() // 只改颜色时也要重绘行首颜色条
+				})
+			}),
+			fyne.NewMenuItem("规范化标签…", func() {
+				renames := tagNormalization(todos)
+				if len(renames) == 0 {
+					showTemporaryPopUp(win.Canvas(), "标签均已是规范形式", 2)
+					return
+				}
+				var lines []string
+				for from, to := range renames {
+					lines = append(lines, from+" → "+to)
+				}
+				slices.Sort(lines)
+				dialog.ShowConfirm("规范化标签", "将去掉首尾空白并统一为小写，重复的合并为一个：\n\n"+strings.Join(lines, "\n"),
+					func(ok bool) {
+						if ok {
+							renameAllTags("规范化标签", renames)
+							renameTagColors(renames)
+						}
+					}, win)
+			}),
+			fyne.NewMenuItem("逾期全部顺延…", func() {
+				now := time.Now()
+				overdue := 0
+				for _, t := range todos {
+					if t.overdue(now) {
+						overdue++
+					}
+				}
+				if overdue == 0 {
+					showTemporaryPopUp(win.Canvas(), "没有逾期的待办", 2)
+					return
+				}
+				day := widget.NewEntry()
+				day.SetText("今天")
+				day.Validator = func(s string) error {
+					if _, ok := parseDue(strings.TrimSpace(s), time.Now()); !ok {
+						return errors.New("无法识别的日期，例如 今天、明天、01-02、2026-01-02")
+					}
+					return nil
+				}
+				dayItem := widget.NewFormItem("顺延到", day)
+				dayItem.HintText = fmt.Sprintf("共 %d 条逾期，保留原来的时刻", overdue)
+				dialog.ShowForm("逾期全部顺延", "顺延", "取消", []*widget.FormItem{dayItem}, func(ok bool) {
+					if !ok {
+						return
+					}
+					now := time.Now()
+					to, _ := parseDue(strings.TrimSpace(day.Text), now)
+					hist.record("逾期顺延", todos)
+					var changes []changeEntry
+					for i := range todos {
+						if todos[i].overdue(now) {
+							due := shiftDue(*todos[i].Due, to, now)
+							todos[i].Due, todos[i].UpdatedAt = &due, now
+							changes = append(changes, todoChange("逾期顺延", todos[i]))
+						}
+					}
+					logChanges(a.Preferences(), changes...)
+					saveTodos(todos)
+					refreshList()
+					showTemporaryPopUp(win.Canvas(), fmt.Sprintf("已顺延 %d 条，Ctrl+Z 撤销", len(changes)), 3)
+				}, win)
+			}),
+			fyne.NewMenuItem("合并重复项…", func() {
+				showDedupeDialog(win, todos, func(ids []string) {
+					hist.record("合并重复项", todos)
+					var changes []changeEntry
+					for _, id := range ids {
+						if i := indexOf(id); i >= 0 {
+							changes = append(changes, todoChange("合并重复项", todos[i]))
+							todos = slices.Delete(todos, i, i+1)
+						}
+					}
+					logChanges(a.Preferences(), changes...)
+					saveTodos(todos)
+					refreshList()
+				})
+			}),
+			fyne.NewMenuItem("回顾久未处理…", func() { runReview(true) }),
+			fyne.NewMenuItemSeparator(),
+			zoomMenuItem(),
+			fyne.NewMenuItem("快捷键", func() { showShortcutHelp(win, keyActions) }),
+		}
+	}
+	var listMenuBtn *widget.Button
+	listMenuBtn = widget.NewButtonWithIcon("", theme.MenuIcon(), func() {
+		widget.ShowPopUpMenuAtRelativePosition(fyne.NewMenu("", listMenuItems()...), win.Canvas(),
+			fyne.NewPos(0, listMenuBtn.Size().Height), listMenuBtn)
 	})
+	listMenuBtn.Importance = widget.LowImportance
 
-	var refreshList func()
+	// shownPages 开启行数上限时已加载的批数
+	shownPages := 1
 	refreshList = func() {
+		focus.update(todos)
+		// 情境筛选只列出正在使用的情境，所选情境不再使用时回到全部
+		contextFilter.Options = contextFilterOptions(todos)
+		if !slices.Contains(contextFilter.Options, contextFilter.Selected) {
+			contextFilter.Selected = contextAll
+		}
+		contextFilter.Refresh()
 		listBox.Objects = nil
-		for i, todo := range todos {
-			index := i
+		rowOf = map[fyne.Focusable]string{}
+		checks = nil
+		filterHex, filtering := colorFilterHex(colorFilter.Selected)
+		filterMode := a.Preferences().StringWithFallback(prefFilterMode, filterAnd)
+		filterChips.Objects = nil
+		for _, f := range []struct {
+			sel    *widget.Select
+			active bool
+			reset  string
+		}{
+			{viewFilter, viewFilter.Selected != viewOptions[0], viewOptions[0]},
+			{contextFilter, contextFilter.Selected != contextAll, contextAll},
+			{colorFilter, filtering, colorFilter.Options[0]},
+		} {
+			if f.active {
+				sel, reset := f.sel, f.reset
+				chip := widget.NewButtonWithIcon(sel.Selected, theme.CancelIcon(), func() { sel.SetSelected(reset) })
+				chip.Importance = widget.LowImportance
+				filterChips.Add(chip)
+			}
+		}
+		if len(filterChips.Objects) > 1 && filterMode == filterOr {
+			filterChips.Objects = append([]fyne.CanvasObject{widget.NewLabel("任一：")}, filterChips.Objects...)
+		}
+		filterChips.Hidden = len(filterChips.Objects) == 0
+		filterChips.Refresh()
+		maxLines := rowMaxLines(a.Preferences())
+		emphasis := a.Preferences().String(prefEmphasis)
+		density := a.Preferences().StringWithFallback(prefDensity, densityNormal)
+		status.update(a.Preferences(), todos, viewFilter.Selected, time.Now())
+		now := time.Now()
+		// 开启行数上限时先筛选再截取，其余的通过"显示更多"分批加载
+		limit := rowLimit(a.Preferences())
+		if limit > 0 {
+			limit *= shownPages
+		}
+		hidden := 0
+		// 倒序显示只影响显示顺序，各行的操作都通过 ID 找到对应的待办
+		order := todos
+		if a.Preferences().Bool(prefNewestFirst) {
+			order = slices.Clone(todos)
+			slices.Reverse(order)
+		}
+		for _, todo := range order {
+			id := todo.ID
+			if !matchFilters(filterMode,
+				filterResult{filtering, !filtering || sameColor(rowColor(todo), filterHex)},
+				filterResult{viewFilter.Selected != viewOptions[0], viewMatch(viewFilter.Selected, todo, now)},
+				filterResult{contextFilter.Selected != contextAll, contextMatch(contextFilter.Selected, todo)},
+			) {
+				continue
+			}
+			if limit > 0 && len(checks) >= limit {
+				hidden++
+				continue
+			}
 
-			label := widget.NewLabel(todo.Text)
-			label.Wrapping = fyne.TextWrapWord
+			var startEdit func()
+			shown := todo.Text
+			if a.Preferences().Bool(prefLinkTitles) {
+				shown = linkDisplay(todo.Text, func() { refreshList() })
+			}
+			if todo.Done && todo.Outcome != "" {
+				shown += " — " + todo.Outcome
+			}
+			label := newTapLabel(shown, maxLines)
 			label.Alignment = fyne.TextAlignLeading
+			blocker, blocked := blockedBy(todo, todos)
+			if todo.Done || todo.Status == StatusWaiting || blocked {
+				label.Importance = widget.LowImportance
+			}
+			if todo.Priority == PriorityHigh {
+				emphasize(&label.Label, emphasis)
+			}
+			label.onTap = func() { pane.show(todo) }
+			label.onDoubleTap = func() {
+				if a.Preferences().BoolWithFallback(prefDoubleTapEdit, true) {
+					startEdit()
+				}
+			}
 
 			copyBtn := widget.NewButton("复制", func() {
-				a.Clipboard().SetContent(todo.Text)
-				showTemporaryPopUp(win.Canvas(), "已复制到剪贴板", 2)
+				copyText(todo.Text)
 			})
 			copyBtn.Importance = widget.LowImportance
 
-			check := widget.NewCheck("", func(done bool) {
-				if done {
-					todos = append(todos[:index], todos[index+1:]...)
-					saveTodos(todos)
-					refreshList()
+			colorBtn := widget.NewButtonWithIcon("", theme.ColorPaletteIcon(), func() {
+				showColorPicker(win, func(hex string) {
+					updateAt(id, "修改颜色", func(t *Todo) { t.Color = hex })
+				})
+			})
+			colorBtn.Importance = widget.LowImportance
+
+			// 用键盘（空格）完成时，焦点移到补位的下一行，可连续按空格快速清理
+			pos := len(checks)
+			check := widget.NewCheck("", nil)
+			check.Checked = todo.Done
+			check.OnChanged = func(done bool) {
+				// 取消勾选已完成的一条时恢复为未完成
+				if !done {
+					if todo.Done {
+						updateAt(id, "取消完成", func(t *Todo) { t.Done, t.CompletedAt, t.Outcome = false, nil, "" })
+					}
+					return
+				}
+				byKeyboard := win.Canvas().Focused() == check
+				completeAt(id)
+				if byKeyboard && a.Preferences().BoolWithFallback(prefCompleteAdvance, true) && len(checks) > 0 {
+					win.Canvas().Focus(checks[min(pos, len(checks)-1)])
+				}
+			}
+			checks = append(checks, check)
+
+			actions := container.NewHBox()
+			// 优先级标记：单击依次切换 无→低→中→高→无，立即保存
+			prioBtn := widget.NewButton(priorityBadge(todo.Priority), func() {
+				updateAt(id, "修改优先级", func(t *Todo) { t.Priority = (t.Priority + 1) % (PriorityHigh + 1) })
+			})
+			prioBtn.Importance = priorityImportance[todo.Priority]
+			if density != densityMinimal {
+				actions.Add(prioBtn)
+				rowOf[prioBtn] = id
+			}
+			if todo.Attachment != "" {
+				attachBtn := widget.NewButtonWithIcon("", theme.MailAttachmentIcon(), func() {
+					openAttachment(a, win, todo.Attachment)
+				})
+				attachBtn.Importance = widget.LowImportance
+				actions.Add(attachBtn)
+				rowOf[attachBtn] = id
+			}
+
+			// 更多操作菜单
+			var moreBtn *widget.Button
+			moreBtn = widget.NewButtonWithIcon("", theme.MoreHorizontalIcon(), func() {
+				items := []*fyne.MenuItem{
+					fyne.NewMenuItem("编辑", func() { startEdit() }),
+					fyne.NewMenuItem("分享", func() { shareText(a, win, todo.Text) }),
+					fyne.NewMenuItem("在上方插入…", func() { insertNear(id, false) }),
+					fyne.NewMenuItem("在下方插入…", func() { insertNear(id, true) }),
+					fyne.NewMenuItem("设置附件…", func() {
+						chooseAttachment(win, func(path string) {
+							updateAt(id, "设置附件", func(t *Todo) { t.Attachment = path })
+						})
+					}),
+				}
+				if todo.Attachment != "" {
+					items = append(items, fyne.NewMenuItem("在文件夹中显示", func() {
+						revealAttachment(a, win, todo.Attachment)
+					}))
+					items = append(items, fyne.NewMenuItem("移除附件", func() {
+						updateAt(id, "移除附件", func(t *Todo) { t.Attachment = "" })
+					}))
+				}
+				if todo.Status == StatusWaiting {
+					items = append(items, fyne.NewMenuItem("恢复为进行中", func() {
+						updateAt(id, "恢复为进行中", func(t *Todo) { t.Status = StatusActive })
+					}))
+				} else {
+					items = append(items, fyne.NewMenuItem("标记为等待中", func() {
+						updateAt(id, "标记为等待中", func(t *Todo) { t.Status = StatusWaiting })
+					}))
+				}
+				hideTray := fyne.NewMenuItem("不在托盘显示", func() {
+					updateAt(id, "设置托盘显示", func(t *Todo) { t.HideInTray = !t.HideInTray })
+				})
+				hideTray.Checked = todo.HideInTray
+				items = append(items, hideTray)
+				items = append(items, fyne.NewMenuItem("依赖于…", func() {
+					var candidates []Todo
+					for _, t := range todos {
+						if t.ID != todo.ID && !dependsOnChain(t, todo.ID, todos) {
+							candidates = append(candidates, t)
+						}
+					}
+					showDependencyDialog(win, candidates, func(depID string) {
+						updateAt(id, "设置依赖", func(t *Todo) { t.DependsOn = depID })
+					})
+				}))
+				if todo.DependsOn != "" {
+					items = append(items, fyne.NewMenuItem("取消依赖", func() {
+						updateAt(id, "取消依赖", func(t *Todo) { t.DependsOn = "" })
+					}))
+				}
+				// 情境：常用与已用的情境，另可自定义
+				contextItem := fyne.NewMenuItem("情境", nil)
+				contextItem.ChildMenu = fyne.NewMenu("")
+				for _, c := range contextChoices(todos) {
+					item := fyne.NewMenuItem(c, func() {
+						updateAt(id, "设置情境", func(t *Todo) { t.Context = c })
+					})
+					item.Checked = todo.Context == c
+					contextItem.ChildMenu.Items = append(contextItem.ChildMenu.Items, item)
+				}
+				contextItem.ChildMenu.Items = append(contextItem.ChildMenu.Items, fyne.NewMenuItemSeparator(),
+					fyne.NewMenuItem("自定义…", func() {
+						entry := widget.NewEntry()
+						entry.SetPlaceHolder("@学校")
+						dialog.ShowForm("情境", "确定", "取消", []*widget.FormItem{
+							widget.NewFormItem("名称", entry),
+						}, func(ok bool) {
+							if ok {
+								updateAt(id, "设置情境", func(t *Todo) { t.Context = normalizeContext(entry.Text) })
+							}
+						}, win)
+					}))
+				if todo.Context != "" {
+					contextItem.ChildMenu.Items = append(contextItem.ChildMenu.Items,
+						fyne.NewMenuItem("清除情境", func() {
+							updateAt(id, "清除情境", func(t *Todo) { t.Context = "" })
+						}))
+				}
+				items = append(items, contextItem)
+				// 开始日期：推迟到某天之前不在列表中显示
+				later := func(days int) func() {
+					return func() {
+						start := startOfDay(time.Now()).AddDate(0, 0, days).UTC()
+						updateAt(id, "推迟", func(t *Todo) { t.StartAt = &start })
+					}
+				}
+				deferItem := fyne.NewMenuItem("推迟到", nil)
+				deferItem.ChildMenu = fyne.NewMenu("",
+					fyne.NewMenuItem("明天", later(1)),
+					fyne.NewMenuItem("后天", later(2)),
+					fyne.NewMenuItem("一周后", later(7)),
+					fyne.NewMenuItem("一个月后", later(30)),
+				)
+				if todo.StartAt != nil {
+					deferItem.ChildMenu.Items = append(deferItem.ChildMenu.Items, fyne.NewMenuItemSeparator(),
+						fyne.NewMenuItem("取消推迟", func() {
+							updateAt(id, "取消推迟", func(t *Todo) { t.StartAt = nil })
+						}))
+				}
+				items = append(items, deferItem)
+				// 截止时间顺延到下一个星期几，保留原来的时刻；没有截止时间时取当天 23:59。
+				// 提醒按新的截止时间重新计算，到点后照常再次提醒
+				snoozeItem := fyne.NewMenuItem("顺延到", nil)
+				snoozeItem.ChildMenu = fyne.NewMenu("")
+				for _, wd := range []time.Weekday{time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday, time.Saturday, time.Sunday} {
+					snoozeItem.ChildMenu.Items = append(snoozeItem.ChildMenu.Items, fyne.NewMenuItem(weekdayNames[wd], func() {
+						now := time.Now()
+						day := nextWeekday(now, wd, a.Preferences().Bool(prefSnoozeToday))
+						updateAt(id, "顺延", func(t *Todo) {
+							due := time.Date(day.Year(), day.Month(), day.Day(), 23, 59, 0, 0, time.Local).UTC()
+							if t.Due != nil {
+								due = shiftDue(*t.Due, day, now)
+							}
+							t.Due = &due
+						})
+					}))
+				}
+				items = append(items, snoozeItem)
+				if todo.Due != nil {
+					remind := fyne.NewMenuItem("提前提醒", nil)
+					def := fyne.NewMenuItem("默认（"+remindLabel(a.Preferences().Int(prefRemindBefore))+"）", func() {
+						updateAt(id, "设置提醒", func(t *Todo) { t.RemindBefore = nil })
+					})
+					def.Checked = todo.RemindBefore == nil
+					remind.ChildMenu = fyne.NewMenu("", def)
+					for _, o := range remindOffsets {
+						minutes := o.minutes
+						item := fyne.NewMenuItem(o.label, func() {
+							updateAt(id, "设置提醒", func(t *Todo) { t.RemindBefore = &minutes })
+						})
+						item.Checked = todo.RemindBefore != nil && *todo.RemindBefore == minutes
+						remind.ChildMenu.Items = append(remind.ChildMenu.Items, item)
+					}
+					items = append(items, remind)
+					sound := fyne.NewMenuItem("提醒声音", nil)
+					defSound := fyne.NewMenuItem("默认（"+soundLabel(a.Preferences().StringWithFallback(prefRemindSound, soundSystem))+"）", func() {
+						updateAt(id, "设置提醒声音", func(t *Todo) { t.Sound = "" })
+					})
+					defSound.Checked = todo.Sound == ""
+					sound.ChildMenu = fyne.NewMenu("", defSound)
+					for _, o := range soundOptions {
+						s := o.sound
+						item := fyne.NewMenuItem(o.label, func() {
+							updateAt(id, "设置提醒声音", func(t *Todo) { t.Sound = s })
+						})
+						item.Checked = todo.Sound == s
+						sound.ChildMenu.Items = append(sound.ChildMenu.Items, item)
+					}
+					items = append(items, sound)
 				}
+				items = append(items, fyne.NewMenuItem("拆分…", func() {
+					showSplitDialog(win, todo.Text, func(parts []string) { splitAt(id, parts) })
+				}))
+				items = append(items, fyne.NewMenuItem("详情…", func() {
+					dialog.ShowInformation("详情", todo.detailText(), win)
+				}))
+				widget.ShowPopUpMenuAtRelativePosition(fyne.NewMenu("", items...), win.Canvas(),
+					fyne.NewPos(0, moreBtn.Size().Height), moreBtn)
 			})
+			moreBtn.Importance = widget.LowImportance
+			actions.Add(colorBtn)
+			actions.Add(copyBtn)
+			actions.Add(moreBtn)
 
-			// 核心布局：左侧复选框 + 中间文字（自动填充） + 右侧复制按钮
-			row := container.NewBorder(nil, nil, check, copyBtn, label)
-			card := container.NewVBox(row, widget.NewSeparator())
+			rowOf[check] = id
+			rowOf[colorBtn] = id
+			rowOf[copyBtn] = id
+			rowOf[moreBtn] = id
+
+			// 核心布局：左侧颜色条与复选框 + 中间文字（自动填充） + 右侧按钮
+			// 从右到左的文字镜像整行：复选框在右，操作按钮在左，文字右对齐
+			rtl := rowRTL(a.Preferences(), todo.Text)
+			left := fyne.CanvasObject(check)
+			if bar := colorBar(rowColor(todo)); bar != nil {
+				left = container.NewHBox(bar, check)
+				if rtl {
+					left = container.NewHBox(check, bar)
+				}
+			}
+			// 行首序号从 1 开始，按设置取显示顺序或保存顺序
+			if mode := a.Preferences().String(prefRowNumbers); mode != rowNumbersOff {
+				n := pos + 1
+				if mode == rowNumbersStorage {
+					n = indexOf(id) + 1
+				}
+				num := widget.NewLabel(strconv.Itoa(n))
+				num.TextStyle = fyne.TextStyle{Monospace: true}
+				num.Importance = widget.LowImportance
+				if rtl {
+					left = container.NewHBox(left, num)
+				} else {
+					left = container.NewHBox(num, left)
+				}
+			}
+			if rtl {
+				label.Alignment = fyne.TextAlignTrailing
+				slices.Reverse(actions.Objects)
+			}
+			body := fyne.CanvasObject(label)
+			meta := todo.rowMetaText(density, now)
+			if blocked && density != densityMinimal {
+				meta = strings.TrimSpace("⛓ " + blocker.Text + "  " + meta)
+			}
+			if meta != "" {
+				metaLabel := widget.NewLabel(meta)
+				metaLabel.SizeName = theme.SizeNameCaptionText
+				metaLabel.Importance = widget.LowImportance
+				if rtl {
+					metaLabel.Alignment = fyne.TextAlignTrailing
+				}
+				if todo.overdue(now) {
+					metaLabel.Importance = widget.DangerImportance
+				}
+				body = container.NewVBox(label, metaLabel)
+			}
+
+			// 行内编辑：用输入框替换文字，回车保存，Esc 或失去焦点放弃
+			center := container.NewStack(body)
+			startEdit = func() {
+				entry := newEscEntry()
+				entry.SetText(todo.Text)
+				entry.Validator = func(text string) error {
+					_, err := checkTodoText(a.Preferences(), text)
+					return err
+				}
+				cancel := func() {
+					center.Objects = []fyne.CanvasObject{body}
+					center.Refresh()
+				}
+				entry.onEscape = cancel
+				entry.onFocusLost = cancel
+				entry.OnSubmitted = func(text string) {
+					text, err := checkTodoText(a.Preferences(), text)
+					if err != nil {
+						entry.SetValidationError(err)
+						return
+					}
+					entry.onFocusLost = nil
+					updateAt(id, "编辑", func(t *Todo) { t.Text = text })
+				}
+				center.Objects = []fyne.CanvasObject{entry}
+				center.Refresh()
+				win.Canvas().Focus(entry)
+			}
+
+			row := container.NewBorder(nil, nil, left, actions, center)
+			if rtl {
+				row = container.NewBorder(nil, nil, actions, left, center)
+			}
+			card := container.NewVBox(row)
+			if sep := rowSeparator(a.Preferences().String(prefSeparator)); sep != nil {
+				card.Add(sep)
+			}
+			// 开启滑动操作后整行可左右拖动；默认关闭，以免鼠标拖动时误触
+			swipeL, swipeR := a.Preferences().String(prefSwipeLeft), a.Preferences().String(prefSwipeRight)
+			if swipeL != swipeNone || swipeR != swipeNone {
+				listBox.Add(newSwipeRow(card, func(dir int) {
+					action := swipeL
+					if dir > 0 {
+						action = swipeR
+					}
+					switch action {
+					case swipeComplete:
+						completeAt(id)
+					case swipeDelete:
+						deleteAt(id)
+					}
+				}))
+				continue
+			}
 			listBox.Add(card)
 		}
+		if hidden > 0 {
+			more := widget.NewButton(fmt.Sprintf("显示更多（还有 %d 条）", hidden), func() {
+				shownPages++
+				refreshList()
+			})
+			more.Importance = widget.LowImportance
+			listBox.Add(more)
+		}
 		listBox.Refresh()
+		restoreScroll()
+		if rescheduleReminders != nil {
+			rescheduleReminders()
+		}
+		if rebuildTray != nil {
+			rebuildTray()
+		}
+		if mini != nil {
+			mini.update(todos, completeAt)
+		}
+		pane.sync(todos)
+	}
+	// 切换筛选后重新从第一批开始显示
+	colorFilter.OnChanged = func(string) {
+		shownPages = 1
+		refreshList()
+	}
+	viewFilter.OnChanged = func(string) {
+		shownPages = 1
+		refreshList()
+	}
+	contextFilter.OnChanged = func(string) {
+		shownPages = 1
+		refreshList()
+	}
+
+	// copyFocused 焦点在某一行时复制该行；输入框获得焦点时由其自行处理复制
+	copyFocused := func() {
+		if i := indexOf(rowOf[win.Canvas().Focused()]); i >= 0 {
+			copyText(todos[i].Text)
+		}
 	}
 
+	// applyHistory 撤销、重做列表操作；输入框获得焦点时由其自行处理
+	applyHistory := func(step func([]Todo) ([]Todo, string, bool), verb string) {
+		prev, label, ok := step(todos)
+		if !ok {
+			showTemporaryPopUp(win.Canvas(), "没有可"+verb+"的操作", 1)
+			return
+		}
+		todos = prev
+		logChanges(a.Preferences(), changeEntry{Action: verb + "：" + label})
+		saveTodos(todos)
+		refreshList()
+		showTemporaryPopUp(win.Canvas(), "已"+verb+"："+label, 2)
+	}
+
+	// 输入时即时校验，空输入框是正常状态不标红
+	input.Validator = func(text string) error {
+		if text == "" {
+			return nil
+		}
+		_, err := newTodo(a.Preferences(), text, time.Now())
+		return err
+	}
+
+	// 开启智能识别时，输入 # 后列出已有标签供补全，常用的在前
+	tagComplete := newTagCompleter(input, func() []string { return tagsByFrequency(todos) })
+	input.OnChanged = func(string) {
+		if a.Preferences().Bool(prefSmartTokens) {
+			tagComplete.update()
+		}
+	}
+	input.onKey = tagComplete.typedKey
+
+	// 高级添加：在输入框下方填写截止时间、优先级与标签
+	adv := newAdvancedAdd(a.Preferences())
+
 	// 输入框回车事件（限制长度）
 	input.OnSubmitted = func(text string) {
-		if text == "" {
+		change, err := adv.fields()
+		if err != nil {
+			showTemporaryPopUp(win.Canvas(), err.Error(), 2)
 			return
 		}
-		if utf8.RuneCountInString(text) > maxLen {
-			showTemporaryPopUp(win.Canvas(), "待办事项最多50个汉字", 2)
+		err = insertText(applyCaptureTemplate(a.Preferences().String(prefCaptureTemplate), text, time.Now()), sourceUI, len(todos), change)
+		if err == errEmptyTodo {
+			return
+		}
+		if err != nil {
+			showTemporaryPopUp(win.Canvas(), err.Error(), 2)
 			return
 		}
-		todos = append(todos, Todo{Text: text})
-		saveTodos(todos)
 		input.SetText("")
-		refreshList()
+		if change != nil {
+			adv.reset()
+		}
+		// 新条目追加在末尾，滚动到底部确保可见；倒序显示时在最上面
+		if a.Preferences().Bool(prefNewestFirst) {
+			listScroll.ScrollToTop()
+		} else {
+			listScroll.ScrollToBottom()
+		}
+	}
+
+	addBtn := widget.NewButtonWithIcon("添加", theme.ContentAddIcon(), func() {
+		input.OnSubmitted(input.Text)
+	})
+	addBtn.Importance = widget.HighImportance
+
+	quickAddText := func(text string) error { return addText(text, sourceQuickAdd) }
+
+	// repeatLast 按最近一次添加的文字再添加一条，行内标记照常识别
+	repeatLast := func() {
+		if lastAdded == "" {
+			showTemporaryPopUp(win.Canvas(), "本次运行还没有添加过待办", 1)
+			return
+		}
+		if err := addText(lastAdded, sourceUI); err != nil {
+			showTemporaryPopUp(win.Canvas(), err.Error(), 2)
+			return
+		}
+		showTemporaryPopUp(win.Canvas(), "已再次添加："+truncateRunes(lastAdded, trayTextLen(a.Preferences())), 1)
+	}
+
+	// focusNextDue 按截止时间把焦点移到下一条显示中的未完成待办并滚动到可见，
+	// 连续调用时依次循环；lastDue 为上次跳到的待办
+	lastDue := ""
+	focusNextDue := func() {
+		if focusMode {
+			return
+		}
+		type dueRow struct {
+			id    string
+			due   time.Time
+			check *widget.Check
+		}
+		var rows []dueRow
+		for _, check := range checks {
+			id := rowOf[check]
+			if i := indexOf(id); i >= 0 && todos[i].Due != nil && !todos[i].Done {
+				rows = append(rows, dueRow{id, *todos[i].Due, check})
+			}
+		}
+		if len(rows) == 0 {
+			showTemporaryPopUp(win.Canvas(), "没有带截止时间的待办", 1)
+			return
+		}
+		slices.SortStableFunc(rows, func(x, y dueRow) int { return x.due.Compare(y.due) })
+		next := 0
+		if i := slices.IndexFunc(rows, func(r dueRow) bool { return r.id == lastDue }); i >= 0 {
+			next = (i + 1) % len(rows)
+		}
+		row := rows[next]
+		lastDue = row.id
+		showMainWindow()
+		d := fyne.CurrentApp().Driver()
+		y := d.AbsolutePositionForObject(row.check).Y - d.AbsolutePositionForObject(listScroll.Content).Y
+		if y < listScroll.Offset.Y || y+row.check.Size().Height > listScroll.Offset.Y+listScroll.Size().Height {
+			listScroll.ScrollToOffset(fyne.NewPos(0, max(y-listScroll.Size().Height/3, 0)))
+		}
+		win.Canvas().Focus(row.check)
+	}
+
+	// Ctrl+P 命令面板：列出常用操作、快捷键登记表中的操作、各筛选项与列表菜单中的全部操作
+	paletteCommands := func() []command {
+		cmds := []command{
+			{name: "添加待办", run: func() {
+				if focusMode {
+					return
+				}
+				win.Canvas().Focus(input)
+			}},
+			{name: "快速添加", run: func() { showQuickAdd(a, quickAddText) }},
+			{name: "专注模式", run: enterFocus},
+			{name: "便签", run: func() { showNotes(a) }},
+			{name: "设置", run: func() { showSettings(a, applyPrefs) }},
+		}
+		cmds = append(cmds, keyCommands(keyActions)...)
+		for _, opt := range viewOptions {
+			cmds = append(cmds, command{name: "视图：" + opt, run: func() { viewFilter.SetSelected(opt) }})
+		}
+		for _, opt := range contextFilter.Options {
+			cmds = append(cmds, command{name: "情境：" + opt, run: func() { contextFilter.SetSelected(opt) }})
+		}
+		for _, opt := range colorFilter.Options {
+			cmds = append(cmds, command{name: "颜色：" + opt, run: func() { colorFilter.SetSelected(opt) }})
+		}
+		return append(cmds, menuCommands(listMenuItems())...)
+	}
+
+	// 快捷键登记表：帮助按此顺序列出，有 shortcuts 的注册到窗口。新增快捷键时只需在此登记
+	zoomBy := func(step int) func() {
+		return func() { setZoom(zoomPercent(a.Preferences()) + step) }
+	}
+	keyActions = []keyAction{
+		{keys: "回车", help: "添加输入框中的待办"},
+		{keys: "Shift+回车", help: "在输入框中换行（需在设置中开启）；撰写模式下添加"},
+		{keys: "↑ / ↓ / 回车 / Esc", help: "输入 # 时选择、插入或关闭标签补全"},
+		{keys: "Tab / Shift+Tab", help: "在各行的控件之间移动焦点"},
+		{keys: "空格", help: "完成焦点所在的一条"},
+		{keys: "Ctrl+C", help: "复制焦点所在一行的文字", shortcuts: []fyne.Shortcut{&fyne.ShortcutCopy{}}, run: copyFocused},
+		{keys: "Ctrl+Z", help: "撤销上一步操作", command: "撤销", shortcuts: []fyne.Shortcut{&fyne.ShortcutUndo{}},
+			run: func() { applyHistory(hist.undo, "撤销") }},
+		{keys: "Ctrl+Y", help: "重做", command: "重做", shortcuts: []fyne.Shortcut{&fyne.ShortcutRedo{}},
+			run: func() { applyHistory(hist.redo, "重做") }},
+		{keys: "Ctrl+P", help: "打开命令面板", shortcuts: []fyne.Shortcut{ctrlKey(fyne.KeyP)}, run: func() {
+			if !focusMode {
+				showCommandPalette(win, paletteCommands())
+			}
+		}},
+		{keys: "Ctrl+D", help: "依次跳到最近截止的待办", command: "下一个截止", shortcuts: []fyne.Shortcut{ctrlKey(fyne.KeyD)}, run: focusNextDue},
+		{keys: "Ctrl+R", help: "再添加一条与上一条相同的待办", command: "重复上一条",
+			shortcuts: []fyne.Shortcut{ctrlKey(fyne.KeyR)}, run: func() {
+				if !focusMode {
+					repeatLast()
+				}
+			}},
+		{keys: "Ctrl+= / Ctrl++", help: "放大界面", command: "放大界面", run: zoomBy(zoomStep),
+			shortcuts: []fyne.Shortcut{ctrlKey(fyne.KeyEqual), ctrlKey(fyne.KeyPlus)}},
+		{keys: "Ctrl+-", help: "缩小界面", command: "缩小界面", shortcuts: []fyne.Shortcut{ctrlKey(fyne.KeyMinus)}, run: zoomBy(-zoomStep)},
+		{keys: "Ctrl+0", help: "还原界面大小", command: "还原界面大小",
+			shortcuts: []fyne.Shortcut{ctrlKey(fyne.Key0)}, run: func() { setZoom(defaultZoom) }},
+		{keys: "回车 / Esc", help: "编辑时保存 / 取消"},
+		{keys: "空格 / 回车", help: "专注模式下完成当前一条"},
+		{keys: "Esc", help: "关闭快速添加"},
+		{keys: "F1 / ?", help: "显示本帮助"},
+	}
+	addShortcuts(win.Canvas(), keyActions)
+
+	// 列表视图布局：顶部筛选 + 输入框 + 滚动列表，layoutMain 按偏好设置把输入框放在列表上方或下方
+	// 后台保存时在标题栏显示"保存中…"，失败时提示；立即保存明显较慢时建议改用后台保存
+	saving := widget.NewLabel("保存中…")
+	saving.Importance = widget.LowImportance
+	saving.Hide()
+	onSaveState = func(busy bool, err error) {
+		fyne.Do(func() {
+			if busy {
+				saving.Show()
+				return
+			}
+			saving.Hide()
+			if err != nil {
+				showTemporaryPopUp(win.Canvas(), "保存失败："+err.Error(), 3)
+			}
+		})
+	}
+	onSlowSave = func(d time.Duration) {
+		showTemporaryPopUp(win.Canvas(), fmt.Sprintf("保存用了 %.1f 秒，可在设置中改为后台保存", d.Seconds()), 4)
+	}
+	onLossySave = func() {
+		fyne.Do(func() {
+			dialog.ShowInformation("部分内容未保存", "当前使用 txt 格式，只保存每条的文字。\n颜色、截止时间、标签、依赖等在保存时丢失，重启后不会恢复。\n需要保留这些内容时请使用默认的 json 格式。", win)
+		})
 	}
 
-	// 窗口布局：底部输入框 + 滚动列表
-	win.SetContent(container.NewBorder(
-		nil,
-		container.NewVBox(widget.NewSeparator(), input),
-		nil,
-		nil,
-		container.NewVScroll(container.NewBorder(nil, nil, nil, layout.NewSpacer(), listBox)),
-	))
+	header := container.NewBorder(nil, nil, nil, container.NewHBox(saving, reorderBtn, focusBtn, listMenuBtn),
+		container.NewGridWithColumns(3, viewFilter, contextFilter, colorFilter))
+	layoutMain = func() {
+		top := container.NewVBox(header, filterChips, widget.NewSeparator())
+		bottom := container.NewVBox()
+		// 撰写模式下回车用于换行，另外提供"添加"按钮；高级添加栏紧跟在输入框下方
+		buttons := container.NewHBox(adv.toggle)
+		if composerLines(a.Preferences()) > 0 {
+			buttons.Add(addBtn)
+		}
+		inputRow := container.NewVBox(container.NewBorder(nil, nil, nil, container.NewVBox(buttons), input), adv.row)
+		if a.Preferences().Bool(prefInputTop) {
+			top.Add(inputRow)
+			top.Add(tagComplete.content)
+			top.Add(widget.NewSeparator())
+		} else {
+			bottom.Add(tagComplete.content)
+			bottom.Add(inputRow)
+		}
+		if a.Preferences().BoolWithFallback(prefStatusBar, true) {
+			bottom.Add(status.content)
+		}
+		if len(bottom.Objects) > 0 {
+			bottom.Objects = append([]fyne.CanvasObject{widget.NewSeparator()}, bottom.Objects...)
+		}
+		body.setEnabled(a.Preferences().Bool(prefSplitView))
+		mainView = container.NewBorder(top, bottom, nil, nil, body)
+		showView()
+	}
 
 	refreshList()
+	layoutMain()
+
 	win.Hide()
 
+	// 校验失败或无法解析时提示用户选择继续使用当前内容或恢复备份
+	if corrupted {
+		showMainWindow()
+		msg := "数据文件与校验值不符，可能同步不完整或已损坏。"
+		if unreadable {
+			msg = "数据文件无法解析，可能同步不完整或已损坏。\n不恢复时损坏的文件另存为 " + storePath() + ".broken，从空列表开始。"
+		}
+		if !hasBackup(storePath()) {
+			dialog.ShowInformation("数据校验失败", msg+"\n没有可用的备份，将继续使用当前内容。", win)
+		} else {
+			d := dialog.NewConfirm("数据校验失败", msg+"\n要恢复上一次的备份吗？", func(restore bool) {
+				if !restore {
+					// 移开损坏的文件，以免下次保存时把它轮换成备份
+					if unreadable {
+						if err := os.Rename(storePath(), storePath()+".broken"); err != nil {
+							slog.Warn("move broken data file failed", "err", err)
+						}
+					}
+					return
+				}
+				if err := restoreBackup(storePath()); err != nil {
+					dialog.ShowError(err, win)
+					return
+				}
+				restored, err := loadTodos()
+				if err != nil {
+					dialog.ShowError(err, win)
+					return
+				}
+				hist.record("恢复备份", todos)
+				todos = restored
+				logChanges(a.Preferences(), changeEntry{Action: "恢复备份"})
+				refreshList()
+			}, win)
+			d.SetConfirmText("恢复备份")
+			d.SetDismissText("仍然加载")
+			if unreadable {
+				d.SetDismissText("使用空列表")
+			}
+			d.Show()
+		}
+	} else if len(todos) == 0 && !a.Preferences().Bool(prefWelcomed) {
+		// 首次运行：列表为空时显示窗口和简短说明
+		showMainWindow()
+		showWelcome(win, func() {
+			for _, text := range exampleTodos {
+				addText(text, sourceWelcome)
+			}
+		}, func() {
+			a.Preferences().SetBool(prefWelcomed, true)
+		})
+	}
+	// quit 退出程序；开启"退出前确认"时先写入未保存内容再弹出确认，避免确认期间丢数据
+	quit := func() {
+		if !a.Preferences().Bool(prefConfirmQuit) {
+			a.Quit()
+			return
+		}
+		flushSave()
+		showMainWindow()
+		dialog.ShowConfirm("退出", "确定要退出待办事项吗？", func(ok bool) {
+			if ok {
+				a.Quit()
+			}
+		}, win)
+	}
+
 	// 系统托盘设置
 	if tray, ok := a.(desktop.App); ok {
 		res, err := fyne.LoadResourceFromPath(iconPath)
 		if err != nil {
-			log.Fatal("load tray icon failed:", err)
+			// 图标文件不可用时改用内存中生成的图标，不影响使用
+			slog.Warn("load tray icon failed, using built-in icon", "file", iconPath, "err", err)
+			res = trayIconResource()
 		}
-		tray.SetSystemTrayIcon(res)
 
-		tray.SetSystemTrayMenu(fyne.NewMenu("Todo",
-			fyne.NewMenuItem("打开待办事项", func() {
+		// 可选快捷操作，菜单项由偏好设置决定
+		quickActions := map[string]func(){
+			trayQuickAdd: func() {
+				showQuickAdd(a, quickAddText)
+			},
+			trayDone: func() {
+				viewFilter.SetSelected("已完成")
+				showMainWindow()
+			},
+			trayStats: func() {
+				a.SendNotification(fyne.NewNotification("待办统计", collectMetrics(todos, time.Now()).summary()))
+			},
+			trayTheme: func() {
+				p := a.Preferences()
+				p.SetString(prefThemeVariant, nextThemeVariant(p.String(prefThemeVariant)))
+				applyTheme(a)
+			},
+			trayFocus: func() {
+				enterFocus()
+				showMainWindow()
+			},
+			trayNotes: func() {
+				showNotes(a)
+			},
+			traySettings: func() {
+				showSettings(a, applyPrefs)
+			},
+		}
+		// trayReady 托盘首次设置（可能有启动延迟）之前不更新菜单
+		trayReady := false
+		rebuildTray = func() {
+			if !trayReady {
+				return
+			}
+			items := []*fyne.MenuItem{
+				fyne.NewMenuItem("打开待办事项", func() {
+					fyne.Do(showMainWindow)
+				}),
+			}
+			// 待办子菜单：列出前几条已到开始日期的待办，点击打开主窗口；
+			// 标记为不在托盘显示的既不列出也不计数
+			var listed []*fyne.MenuItem
+			count := 0
+			now := time.Now()
+			for _, t := range todos {
+				if t.Done || t.HideInTray || t.deferred(now) {
+					continue
+				}
+				count++
+				if len(listed) < trayListMax {
+					listed = append(listed, fyne.NewMenuItem(truncateRunes(t.Text, trayTextLen(a.Preferences())), func() {
+						fyne.Do(showMainWindow)
+					}))
+				}
+			}
+			if count > 0 {
+				if count > len(listed) {
+					listed = append(listed, fyne.NewMenuItemSeparator(),
+						fyne.NewMenuItem(fmt.Sprintf("还有 %d 条…", count-len(listed)), func() {
+							fyne.Do(showMainWindow)
+						}))
+				}
+				sub := fyne.NewMenuItem(fmt.Sprintf("待办（%d）", count), nil)
+				sub.ChildMenu = fyne.NewMenu("", listed...)
+				items = append(items, sub)
+			}
+			miniItem := fyne.NewMenuItem(miniTitle, func() {
 				fyne.Do(func() {
-					win.Show()
-					win.RequestFocus()
+					toggleMiniWidget(a, todos, completeAt, rebuildTray)
+					rebuildTray()
 				})
-			}),
-			fyne.NewMenuItem("退出", func() {
-				a.Quit()
-			}),
-		))
+			})
+			miniItem.Checked = mini != nil
+			items = append(items, miniItem)
+			for _, act := range enabledTrayActions(a.Preferences()) {
+				id, run := act.ID, quickActions[act.ID]
+				items = append(items, fyne.NewMenuItem(act.Label, func() {
+					slog.Debug("tray action", "id", id)
+					fyne.Do(run)
+				}))
+			}
+			// 开启"退出改为隐藏"时托盘中只能隐藏窗口，真正退出需进入设置
+			if a.Preferences().Bool(prefQuitHides) {
+				items = append(items, fyne.NewMenuItem("隐藏窗口", func() {
+					fyne.Do(func() {
+						saveScroll()
+						win.Hide()
+						winVisible = false
+					})
+				}))
+			} else {
+				items = append(items, fyne.NewMenuItem("退出", func() {
+					fyne.Do(quit)
+				}))
+			}
+			tray.SetSystemTrayMenu(fyne.NewMenu("Todo", items...))
+			slog.Debug("tray menu built", "items", len(items))
+		}
+		delay := time.Duration(a.Preferences().Int(prefTrayDelay)) * time.Second
+		setupTray(delay, func() {
+			trayReady = true
+			tray.SetSystemTrayIcon(res)
+			rebuildTray()
+		})
+	}
+
+	// 截止前按提前量发送系统通知，列表变化后重新计算下次检查时间
+	rescheduleReminders = startReminders(a, func() []Todo { return todos })
+	startAutoExport(a.Preferences(), func() []Todo { return todos })
+	startReview(a.Preferences(), func() { runReview(false) })
+
+	// 本机控制接口，供状态栏与脚本使用，协议见 controlRequest
+	if a.Preferences().Bool(prefControlSocket) {
+		stop, err := startControl(controlSocket, func(req controlRequest) controlResponse {
+			switch req.Cmd {
+			case "list":
+				return controlResponse{OK: true, Todos: cloneTodos(todos)}
+			case "count":
+				n := 0
+				for _, t := range todos {
+					if !t.Done {
+						n++
+					}
+				}
+				return controlResponse{OK: true, Count: &n}
+			case "add":
+				if err := addText(req.Text, sourceControl); err != nil {
+					return controlResponse{Error: err.Error()}
+				}
+				return controlResponse{OK: true, ID: todos[len(todos)-1].ID}
+			case "complete":
+				if i := indexOf(req.ID); i < 0 || todos[i].Done {
+					return controlResponse{Error: "no such open item"}
+				}
+				finishAt(req.ID, "")
+				return controlResponse{OK: true}
+			}
+			return controlResponse{Error: "unknown command " + strconv.Quote(req.Cmd)}
+		})
+		if err != nil {
+			slog.Warn("control socket disabled", "path", controlSocket, "err", err)
+		}
+		stopControl = stop
+	}
+	// HTTP 接口：指标供 Prometheus 等定期抓取，只读页面供局域网内浏览，默认只监听本机
+	if metrics, share := a.Preferences().Bool(prefMetrics), a.Preferences().Bool(prefSharePage); metrics || share {
+		addr := a.Preferences().StringWithFallback(prefMetricsAddr, defaultMetricsAddr)
+		stop, err := startHTTP(addr, func() []Todo { return todos }, metrics, share)
+		if err != nil {
+			slog.Warn("http server disabled", "addr", addr, "err", err)
+		}
+		stopHTTP = stop
 	}
 
 	a.Run()
@@ -0,0 +1,153 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+)
+
+// mdColumn Markdown 表格的一列，cell 返回该列在单元格中的内容
+type mdColumn struct {
+	name string
+	cell func(t Todo, todos []Todo) string
+}
+
+// mdColumns Markdown 表格可选的列
+var mdColumns = []mdColumn{
+	{"状态", func(t Todo, todos []Todo) string {
+		if t.Done {
+			return "已完成"
+		}
+		if t.Status == StatusWaiting {
+			return "等待中"
+		}
+		if _, ok := blockedBy(t, todos); ok {
+			return "受阻"
+		}
+		return "进行中"
+	}},
+	{"标题", func(t Todo, _ []Todo) string { return t.Text }},
+	{"优先级", func(t Todo, _ []Todo) string {
+		if t.Priority == PriorityNone {
+			return ""
+		}
+		return priorityLabels[t.Priority]
+	}},
+	{"截止", func(t Todo, _ []Todo) string {
+		if t.Due == nil {
+			return ""
+		}
+		return formatDueInput(*t.Due)
+	}},
+	{"标签", func(t Todo, _ []Todo) string { return strings.Join(t.Tags, " ") }},
+}
+
+// noTagGroup 按标签分组时没有标签的待办所在的分组
+const noTagGroup = "无标签"
+
+// escapeMarkdownCell 转义表格单元格：竖线会拆开单元格，换行会截断表格行
+func escapeMarkdownCell(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, "|", `\|`)
+	return strings.NewReplacer("\r\n", "<br>", "\n", "<br>", "\r", "<br>").Replace(s)
+}
+
+// exportMarkdownTable 生成 Markdown 表格，columns 为 mdColumns 中选中的列名；
+// byTag 为真时按标签分节，带多个标签的待办在每个标签下各出现一次
+func exportMarkdownTable(todos []Todo, columns []string, byTag bool, now time.Time) []byte {
+	// 列的顺序固定按 mdColumns，与勾选的先后无关
+	cols := slices.DeleteFunc(slices.Clone(mdColumns), func(c mdColumn) bool {
+		return !slices.Contains(columns, c.name)
+	})
+	var b strings.Builder
+	fmt.Fprintf(&b, "# 待办事项（%s）\n", now.Format("2006-01-02"))
+	table := func(items []Todo) {
+		b.WriteString("\n|")
+		for _, c := range cols {
+			b.WriteString(" " + c.name + " |")
+		}
+		b.WriteString("\n|")
+		for range cols {
+			b.WriteString(" --- |")
+		}
+		b.WriteString("\n")
+		for _, t := range items {
+			b.WriteString("|")
+			for _, c := range cols {
+				b.WriteString(" " + escapeMarkdownCell(c.cell(t, todos)) + " |")
+			}
+			b.WriteString("\n")
+		}
+	}
+	if !byTag {
+		table(todos)
+		return []byte(b.String())
+	}
+
+	groups := map[string][]Todo{}
+	var names []string
+	add := func(name string, t Todo) {
+		if _, ok := groups[name]; !ok {
+			names = append(names, name)
+		}
+		groups[name] = append(groups[name], t)
+	}
+	for _, t := range todos {
+		if len(t.Tags) == 0 {
+			add(noTagGroup, t)
+		}
+		for _, tag := range t.Tags {
+			add(tag, t)
+		}
+	}
+	// 标签按名称排序，无标签的放在最后
+	slices.SortFunc(names, func(x, y string) int {
+		switch {
+		case x == noTagGroup:
+			return 1
+		case y == noTagGroup:
+			return -1
+		}
+		return strings.Compare(x, y)
+	})
+	for _, name := range names {
+		fmt.Fprintf(&b, "\n## %s\n", name)
+		table(groups[name])
+	}
+	return []byte(b.String())
+}
+
+// showMarkdownTableDialog 选择分组方式与列后导出 Markdown 表格
+func showMarkdownTableDialog(win fyne.Window, p fyne.Preferences, todos []Todo) {
+	var names []string
+	for _, c := range mdColumns {
+		names = append(names, c.name)
+	}
+	columns := widget.NewCheckGroup(names, nil)
+	columns.SetSelected(names)
+	grouping := widget.NewRadioGroup([]string{"不分组", "按标签"}, nil)
+	grouping.Horizontal = true
+	grouping.Required = true
+	grouping.SetSelected("不分组")
+
+	dialog.ShowForm("导出 Markdown 表格", "导出", "取消", []*widget.FormItem{
+		widget.NewFormItem("分组", grouping),
+		widget.NewFormItem("列", columns),
+	}, func(ok bool) {
+		if !ok {
+			return
+		}
+		if len(columns.Selected) == 0 {
+			dialog.ShowInformation("导出 Markdown 表格", "请至少选择一列", win)
+			return
+		}
+		now := time.Now()
+		showExportDialog(win, exportName(p, "md", now),
+			exportMarkdownTable(todos, columns.Selected, grouping.Selected == "按标签", now))
+	}, win)
+}
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+)
+
+// defaultMetricsAddr HTTP 接口（指标与只读页面）的默认监听地址，只接受本机访问
+const defaultMetricsAddr = "127.0.0.1:9464"
+
+// todoMetrics 指标接口输出的计数
+type todoMetrics struct {
+	Total, Active, Done, DoneToday, Overdue int
+}
+
+// collectMetrics 统计当前列表；未开启"完成后保留"时完成的待办已被移除，
+// 因此 done 与 done_today 只包含仍留在列表中的
+func collectMetrics(todos []Todo, now time.Time) todoMetrics {
+	m := todoMetrics{Total: len(todos)}
+	for _, t := range todos {
+		switch {
+		case t.Done:
+			m.Done++
+			if t.CompletedAt != nil && daysBetween(now, t.CompletedAt.In(time.Local)) == 0 {
+				m.DoneToday++
+			}
+		default:
+			m.Active++
+			if t.overdue(now) {
+				m.Overdue++
+			}
+		}
+	}
+	return m
+}
+
+// summary 返回一行中文的计数摘要，用于托盘"统计"
+func (m todoMetrics) summary() string {
+	return fmt.Sprintf("共 %d 条：未完成 %d，逾期 %d，已完成 %d（今天 %d）", m.Total, m.Active, m.Overdue, m.Done, m.DoneToday)
+}
+
+// prometheusText 按 Prometheus 文本格式输出
+func (m todoMetrics) prometheusText() string {
+	var b strings.Builder
+	for _, g := range []struct {
+		name, help string
+		value      int
+	}{
+		{"todo_items_total", "列表中的待办条数", m.Total},
+		{"todo_items_active", "未完成的条数", m.Active},
+		{"todo_items_done", "已完成并保留在列表中的条数", m.Done},
+		{"todo_items_done_today", "今天完成并保留在列表中的条数", m.DoneToday},
+		{"todo_items_overdue", "已过截止时间的未完成条数", m.Overdue},
+	} {
+		fmt.Fprintf(&b, "# HELP %s %s\n# TYPE %s gauge\n%s %d\n", g.name, g.help, g.name, g.name, g.value)
+	}
+	return b.String()
+}
+
+// startHTTP 在 addr 上提供可选的 HTTP 接口：metrics 为 /metrics，share 为只读页面 /。
+// 数据在主线程中读取，每次请求都反映当前列表；返回的函数关闭服务
+func startHTTP(addr string, list func() []Todo, metrics, share bool) (func(), error) {
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+	mux := http.NewServeMux()
+	if metrics {
+		mux.HandleFunc("GET /metrics", func(w http.ResponseWriter, r *http.Request) {
+			var m todoMetrics
+			fyne.DoAndWait(func() { m = collectMetrics(list(), time.Now()) })
+			w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+			_, _ = w.Write([]byte(m.prometheusText()))
+		})
+	}
+	if share {
+		mux.HandleFunc("GET /{$}", func(w http.ResponseWriter, r *http.Request) {
+			var page sharePage
+			fyne.DoAndWait(func() { page = buildSharePage(list(), time.Now()) })
+			w.Header().Set("Content-Type", "text/html; charset=utf-8")
+			w.Header().Set("Cache-Control", "no-store")
+			if err := shareTemplate.Execute(w, page); err != nil {
+				slog.Warn("share page failed", "err", err)
+			}
+		})
+	}
+	srv := &http.Server{Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+	go func() {
+		if err := srv.Serve(ln); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			slog.Warn("http server stopped", "err", err)
+		}
+	}()
+	slog.Debug("http listening", "addr", ln.Addr().String(), "metrics", metrics, "share", share)
+	// 关闭时在主线程中调用，正在处理的请求可能在等待主线程读取列表，
+	// 用 Close 直接断开而不是等它们完成
+	return func() { _ = srv.Close() }, nil
+}
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/widget"
+)
+
+const (
+	miniTitle         = "桌面挂件"
+	defaultMiniWidth  = 240
+	defaultMiniHeight = 320
+)
+
+// miniWidget 桌面挂件：只列出待完成事项的小窗口，勾选即完成。
+// Fyne 的无边框窗口无法拖动，也不能置顶或指定位置，因此使用普通窗口，
+// 在 X11 下借助 wmctrl 置顶并显示在所有工作区；窗口大小单独保存
+type miniWidget struct {
+	win  fyne.Window
+	list *fyne.Container
+}
+
+// mini 当前打开的桌面挂件，未打开时为空
+var mini *miniWidget
+
+// toggleMiniWidget 打开或关闭桌面挂件，挂件关闭后调用 onClosed
+func toggleMiniWidget(a fyne.App, todos []Todo, onComplete func(id string), onClosed func()) {
+	if mini != nil {
+		mini.win.Close()
+		return
+	}
+	showMiniWidget(a, todos, onComplete, onClosed)
+}
+
+// showMiniWidget 打开桌面挂件，已打开时只更新内容
+func showMiniWidget(a fyne.App, todos []Todo, onComplete func(id string), onClosed func()) {
+	if mini != nil {
+		mini.update(todos, onComplete)
+		return
+	}
+	p := a.Preferences()
+	m := &miniWidget{win: a.NewWindow(miniTitle), list: container.NewVBox()}
+	m.win.SetContent(container.NewVScroll(m.list))
+	m.win.Resize(fyne.NewSize(
+		float32(p.IntWithFallback(prefMiniWidth, defaultMiniWidth)),
+		float32(p.IntWithFallback(prefMiniHeight, defaultMiniHeight))))
+	m.win.SetOnClosed(func() {
+		size := m.win.Canvas().Size()
+		p.SetInt(prefMiniWidth, int(size.Width))
+		p.SetInt(prefMiniHeight, int(size.Height))
+		mini = nil
+		if onClosed != nil {
+			onClosed()
+		}
+	})
+	mini = m
+	m.update(todos, onComplete)
+	m.win.Show()
+	setWindowState(miniTitle, "above,sticky", true)
+}
+
+// update 按最新数据重建列表，与主窗口的"全部"视图一致只显示已到开始日期的未完成事项
+func (m *miniWidget) update(todos []Todo, onComplete func(id string)) {
+	m.list.Objects = nil
+	now := time.Now()
+	for _, t := range todos {
+		if t.Done || t.deferred(now) {
+			continue
+		}
+		id := t.ID
+		check := widget.NewCheck("", func(on bool) {
+			if on {
+				onComplete(id)
+			}
+		})
+		label := widget.NewLabel(t.Text)
+		label.Wrapping = fyne.TextWrapWord
+		if t.overdue(now) {
+			label.Importance = widget.DangerImportance
+		}
+		m.list.Add(container.NewBorder(nil, nil, check, nil, label))
+	}
+	if len(m.list.Objects) == 0 {
+		empty := widget.NewLabel("没有待办")
+		empty.Importance = widget.LowImportance
+		m.list.Add(empty)
+	}
+	m.list.Refresh()
+}
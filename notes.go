@@ -0,0 +1,74 @@
+package main
+
+import (
+	"log/slog"
+	"os"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/widget"
+)
+
+const (
+	notesFile  = "notes.txt" // 便签内容，与待办数据分开保存
+	notesDelay = time.Second // 停止输入后多久自动保存
+)
+
+var (
+	notesWin   fyne.Window
+	notesTimer *time.Timer
+	notesText  func() string // 便签窗口打开时返回当前内容，供退出时保存
+)
+
+// writeNotes 写入便签内容，失败时只记录日志
+func writeNotes(text string) {
+	if err := os.WriteFile(notesFile, []byte(text), 0644); err != nil {
+		slog.Error("save notes failed", "file", notesFile, "err", err)
+	}
+}
+
+// flushNotes 立即保存尚未写入的便签，关闭窗口或退出时调用
+func flushNotes() {
+	if notesTimer != nil {
+		notesTimer.Stop()
+		notesTimer = nil
+	}
+	if notesText != nil {
+		writeNotes(notesText())
+	}
+}
+
+// showNotes 打开便签窗口：自由书写的多行文本，输入停顿后自动保存
+func showNotes(a fyne.App) {
+	if notesWin != nil {
+		notesWin.RequestFocus()
+		return
+	}
+	data, err := os.ReadFile(notesFile)
+	if err != nil && !os.IsNotExist(err) {
+		slog.Warn("load notes failed", "file", notesFile, "err", err)
+	}
+
+	entry := widget.NewMultiLineEntry()
+	entry.Wrapping = fyne.TextWrapWord
+	entry.SetPlaceHolder("随手记，自动保存")
+	entry.SetText(string(data))
+	entry.OnChanged = func(text string) {
+		if notesTimer != nil {
+			notesTimer.Stop()
+		}
+		notesTimer = time.AfterFunc(notesDelay, func() { writeNotes(text) })
+	}
+
+	w := a.NewWindow("便签")
+	w.SetContent(entry)
+	w.Resize(fyne.NewSize(360, 400))
+	w.SetOnClosed(func() {
+		flushNotes()
+		notesWin, notesText = nil, nil
+	})
+	notesWin = w
+	notesText = func() string { return entry.Text }
+	w.Show()
+	w.Canvas().Focus(entry)
+}
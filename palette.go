@@ -0,0 +1,167 @@
+package main
+
+import (
+	"slices"
+	"strings"
+	"unicode"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+)
+
+// command 命令面板中的一项操作，keys 为对应的快捷键说明，没有时为空
+type command struct {
+	name string
+	keys string
+	run  func()
+}
+
+// menuCommands 把菜单项转换为命令，跳过分隔线
+func menuCommands(items []*fyne.MenuItem) []command {
+	var cmds []command
+	for _, it := range items {
+		if it.IsSeparator || it.Action == nil {
+			continue
+		}
+		cmds = append(cmds, command{name: strings.TrimSuffix(it.Label, "…"), run: it.Action})
+	}
+	return cmds
+}
+
+// fuzzyScore 模糊匹配：query 的字符按顺序出现在 name 中即为匹配，忽略大小写与空白。
+// 分数越低越靠前：连续命中与靠前命中的得分更低，未匹配返回 -1
+func fuzzyScore(name, query string) int {
+	q := []rune(strings.ToLower(strings.Join(strings.Fields(query), "")))
+	if len(q) == 0 {
+		return 0
+	}
+	score, last, i := 0, -1, 0
+	for pos, r := range []rune(strings.ToLower(name)) {
+		if i == len(q) {
+			break
+		}
+		if unicode.IsSpace(r) || r != q[i] {
+			continue
+		}
+		if last >= 0 {
+			score += pos - last - 1
+		} else {
+			score += pos
+		}
+		last = pos
+		i++
+	}
+	if i < len(q) {
+		return -1
+	}
+	return score
+}
+
+// paletteEntry 命令面板的搜索框，上下键移动选中项，Esc 关闭
+type paletteEntry struct {
+	escEntry
+	onMove func(delta int)
+}
+
+func (e *paletteEntry) TypedKey(key *fyne.KeyEvent) {
+	switch key.Name {
+	case fyne.KeyUp:
+		e.onMove(-1)
+	case fyne.KeyDown:
+		e.onMove(1)
+	default:
+		e.escEntry.TypedKey(key)
+	}
+}
+
+// commandPalette 当前显示的命令面板，同一时间只显示一个
+var commandPalette dialog.Dialog
+
+// showCommandPalette 显示可搜索的命令列表，回车或单击执行选中的命令
+func showCommandPalette(win fyne.Window, cmds []command) {
+	if commandPalette != nil {
+		return
+	}
+	shown := cmds
+	selected := 0
+
+	var d dialog.Dialog
+	run := func(i int) {
+		if i < 0 || i >= len(shown) {
+			return
+		}
+		d.Hide()
+		shown[i].run()
+	}
+
+	list := widget.NewList(
+		func() int { return len(shown) },
+		func() fyne.CanvasObject {
+			keys := widget.NewLabel("")
+			keys.TextStyle = fyne.TextStyle{Monospace: true}
+			keys.Importance = widget.LowImportance
+			return container.NewBorder(nil, nil, nil, keys, widget.NewLabel(""))
+		},
+		func(i widget.ListItemID, o fyne.CanvasObject) {
+			// 键盘选中的一项以高亮文字标出，不使用列表自身的选中状态，
+			// 这样单击任何一项（包括已高亮的）都会执行
+			row := o.(*fyne.Container)
+			name := row.Objects[0].(*widget.Label)
+			name.Importance = widget.MediumImportance
+			if i == selected {
+				name.Importance = widget.HighImportance
+			}
+			name.SetText(shown[i].name)
+			row.Objects[1].(*widget.Label).SetText(shown[i].keys)
+		})
+	list.OnSelected = func(i widget.ListItemID) {
+		list.UnselectAll()
+		run(i)
+	}
+	sel := func(i int) {
+		selected = i
+		list.Refresh()
+		if len(shown) > 0 {
+			list.ScrollTo(i)
+		}
+	}
+
+	entry := &paletteEntry{}
+	entry.ExtendBaseWidget(entry)
+	entry.SetPlaceHolder("输入命令名称，回车执行，Esc 关闭")
+	entry.OnChanged = func(q string) {
+		type hit struct {
+			cmd   command
+			score int
+		}
+		var hits []hit
+		for _, c := range cmds {
+			if s := fuzzyScore(c.name, q); s >= 0 {
+				hits = append(hits, hit{c, s})
+			}
+		}
+		slices.SortStableFunc(hits, func(x, y hit) int { return x.score - y.score })
+		shown = shown[:0:0]
+		for _, h := range hits {
+			shown = append(shown, h.cmd)
+		}
+		sel(0)
+	}
+	entry.onMove = func(delta int) {
+		if len(shown) > 0 {
+			sel((selected + delta + len(shown)) % len(shown))
+		}
+	}
+	entry.OnSubmitted = func(string) { run(selected) }
+	entry.onEscape = func() { d.Hide() }
+
+	d = dialog.NewCustomWithoutButtons("命令面板", container.NewBorder(entry, nil, nil, nil, list), win)
+	d.SetOnClosed(func() { commandPalette = nil })
+	d.Resize(fyne.NewSize(420, 360))
+	commandPalette = d
+	d.Show()
+	sel(0)
+	win.Canvas().Focus(entry)
+}
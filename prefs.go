@@ -0,0 +1,159 @@
+package main
+
+import "fyne.io/fyne/v2"
+
+// 偏好设置键名，均保存在 a.Preferences() 中
+const (
+	prefNormalizeSpace  = "normalizeSpace"  // 输入时合并多余空白，默认开启
+	prefMaxLen          = "maxLen"          // 每条待办的最大字数
+	prefMaxItems        = "maxItems"        // 待办条数上限，0 表示不限
+	prefFocusMode       = "focusMode"       // 上次退出时是否处于专注模式
+	prefTrayActions     = "trayActions"     // 托盘菜单显示的快捷操作
+	prefSmartTokens     = "smartTokens"     // 识别输入中的 !优先级 @日期 #标签，默认关闭
+	prefScrollOffset    = "scrollOffset"    // 列表滚动位置
+	prefCompleteAdvance = "completeAdvance" // 键盘完成后焦点移到下一条，默认开启
+	prefAppIcon         = "appIcon"         // 自定义窗口图标的文件路径，空为内置图标
+	prefDoubleTapEdit   = "doubleTapEdit"   // 双击文字进入编辑，默认开启
+	prefSaveMode        = "saveMode"        // 保存策略：immediate / debounce / quit / background
+	prefConfirmQuit     = "confirmQuit"     // 退出前确认，默认关闭
+	prefRowMaxLines     = "rowMaxLines"     // 每行最多显示的文字行数，0 表示不限
+	prefWelcomed        = "welcomed"        // 是否已显示过首次运行说明
+	prefRemindBefore    = "remindBefore"    // 默认提前提醒的分钟数，0 为到期时提醒
+	prefInputTop        = "inputTop"        // 输入框放在列表上方，默认在底部
+	prefStatusBar       = "statusBar"       // 显示底部状态栏，默认开启
+	prefEmphasis        = "emphasis"        // 高优先级文字样式：空 / bold / large
+	prefCompleteHook    = "completeHook"    // 完成待办时运行的命令模板，空为不运行
+	prefSoftBreak       = "softBreak"       // 主输入框允许 Shift+回车换行，默认关闭
+	prefTrayDelay       = "trayDelay"       // 启动后等待多少秒再设置托盘，0 为立即
+	prefTextDir         = "textDir"         // 文字方向：空为自动 / ltr / rtl
+	prefRemindInterval  = "remindInterval"  // 提醒的最长检查间隔，单位秒
+	prefTrayTextLen     = "trayTextLen"     // 托盘菜单中待办文字的最大字数
+	prefChangeLog       = "changeLog"       // 把每次修改追加到 changes.log，默认关闭
+	prefCopyPopup       = "copyPopup"       // 复制后提示"已复制到剪贴板"，默认开启
+	prefSplitView       = "splitView"       // 窗口足够宽时右侧显示详情面板，默认关闭
+	prefRowLimit        = "rowLimit"        // 列表每批显示的条数，0 表示全部显示
+
+	prefAutoExportDir     = "autoExportDir"     // 自动导出的目标目录，空为不导出
+	prefAutoExportFormat  = "autoExportFormat"  // 自动导出格式：json 或 md
+	prefAutoExportCadence = "autoExportCadence" // 自动导出频率：空 / daily / weekly / quit
+	prefAutoExportKeep    = "autoExportKeep"    // 自动导出保留的份数
+	prefAutoExportLast    = "autoExportLast"    // 上次定时导出的时间
+	prefFocusList         = "focusList"         // 从托盘打开窗口时焦点放在列表而不是输入框
+	prefNewestFirst       = "newestFirst"       // 列表倒序显示，最新添加的在最上面，不改变保存的顺序
+	prefComposerLines     = "composerLines"     // 撰写模式输入框显示的行数，0 为普通的单行输入框
+	prefSwipeLeft         = "swipeLeft"         // 向左滑动一行时的操作，空为不响应
+	prefSwipeRight        = "swipeRight"        // 向右滑动一行时的操作，空为不响应
+	prefAccent            = "accent"            // 主题色：accentPresets 中的名称或 custom
+	prefAccentColor       = "accentColor"       // 自定义主题色，十六进制
+	prefThemeVariant      = "themeVariant"      // 深浅色模式，见 themeVariants，默认跟随系统
+	prefKeepDone          = "keepDone"          // 完成的待办保留在列表中，可取消勾选恢复；默认完成即移除
+	prefSeparator         = "separator"         // 行间分隔线样式：空为实线，dotted 或 none
+	prefControlSocket     = "controlSocket"     // 开启本机控制接口（Unix 套接字），默认关闭
+	prefQuitHides         = "quitHides"         // 托盘菜单的"退出"改为隐藏窗口，真正退出在设置中，默认关闭
+	prefRemindSound       = "remindSound"       // 默认提醒声音，见 soundOptions，默认为系统默认
+	prefCaptureTemplate   = "captureTemplate"   // 输入框添加待办时套用的模板，见 applyCaptureTemplate，空为不使用
+	prefFilterMode        = "filterMode"        // 多个筛选条件的组合方式，见 filterModes，默认为"且"
+	prefZoom              = "zoom"              // 界面缩放百分比，见 zoomPercent，默认 100
+	prefAllWorkspaces     = "allWorkspaces"     // 主窗口显示在所有工作区（仅 X11，需要 wmctrl），默认关闭
+	prefLinkTitles        = "linkTitles"        // 获取链接的网页标题并代替链接显示，会访问网络，默认关闭
+	prefNormalizeTags     = "normalizeTags"     // 添加或编辑时把标签统一为小写并去掉首尾空白，默认关闭
+	prefMiniWidth         = "miniWidth"         // 桌面挂件关闭时的宽度
+	prefMiniHeight        = "miniHeight"        // 桌面挂件关闭时的高度
+	prefExportStamp       = "exportStamp"       // 导出的默认文件名带上时刻，避免覆盖当天已导出的文件，默认关闭
+	prefOutcomePrompt     = "outcomePrompt"     // 在主窗口完成待办时询问结果备注，默认关闭
+	prefWIPWarn           = "wipWarn"           // 进行中的条数超过此值时计数显示为黄色，0 为不提示
+	prefWIPLimit          = "wipLimit"          // 进行中的条数超过此值时计数显示为红色，0 为不提示
+	prefDensity           = "density"           // 列表行显示的附加信息：见 densities，空为 normal
+	prefMetrics           = "metrics"           // 开启 HTTP 指标接口 /metrics，默认关闭
+	prefMetricsAddr       = "metricsAddr"       // HTTP 接口（指标与只读页面）的监听地址，空为 defaultMetricsAddr
+	prefSharePage         = "sharePage"         // 在 HTTP 接口上提供只读的待办页面 /，默认关闭
+	prefAdvancedAdd       = "advancedAdd"       // 输入框下方显示截止时间、优先级与标签输入栏，默认收起
+	prefCopyMode          = "copyMode"          // "复制"复制的内容，见 copyModes，空为全文
+	prefRowNumbers        = "rowNumbers"        // 行首序号：空为不显示，见 rowNumberModes
+	prefCompleteSound     = "completeSound"     // 完成待办时播放内置提示音，默认关闭
+	prefSnoozeToday       = "snoozeToday"       // 顺延到星期几时，今天正是那天则顺延到今天而不是下周，默认关闭
+	prefReviewCadence     = "reviewCadence"     // 回顾久未处理待办的频率，见 reviewCadences，默认关闭
+	prefReviewDays        = "reviewDays"        // 超过多少天没有改动的待办需要回顾
+	prefReviewLast        = "reviewLast"        // 上次定时回顾的时间
+)
+
+const (
+	defaultMaxLen    = 50 // 每条最多50汉字
+	maxMaxLen        = 500
+	maxMaxItems      = 10000
+	maxRowLines      = 20
+	maxRowLimit      = 1000
+	maxComposerLines = 10
+
+	defaultTrayTextLen = 20 // 托盘菜单中每条最多显示的字数
+	minTrayTextLen     = 5
+	maxTrayTextLen     = 100
+)
+
+// 滑动一行时可执行的操作
+const (
+	swipeNone     = ""
+	swipeComplete = "complete"
+	swipeDelete   = "delete"
+)
+
+// maxTextLen 返回每条待办允许的最大字数，非法值回退到默认值
+func maxTextLen(p fyne.Preferences) int {
+	n := p.IntWithFallback(prefMaxLen, defaultMaxLen)
+	if n < 1 || n > maxMaxLen {
+		return defaultMaxLen
+	}
+	return n
+}
+
+// keepDone 完成的待办是否保留在列表中；txt 格式无法保存完成状态，始终不保留
+func keepDone(p fyne.Preferences) bool {
+	_, text := store.(*textStore)
+	return p.Bool(prefKeepDone) && !text
+}
+
+// maxItemCount 返回待办条数上限，0 表示不限
+func maxItemCount(p fyne.Preferences) int {
+	n := p.IntWithFallback(prefMaxItems, 0)
+	if n < 0 || n > maxMaxItems {
+		return 0
+	}
+	return n
+}
+
+// rowMaxLines 返回列表每条最多显示的行数，0 表示完整显示
+func rowMaxLines(p fyne.Preferences) int {
+	n := p.IntWithFallback(prefRowMaxLines, 0)
+	if n < 0 || n > maxRowLines {
+		return 0
+	}
+	return n
+}
+
+// trayTextLen 托盘菜单中待办文字的最大字数，超出范围时收拢到最近的有效值
+func trayTextLen(p fyne.Preferences) int {
+	return min(max(p.IntWithFallback(prefTrayTextLen, defaultTrayTextLen), minTrayTextLen), maxTrayTextLen)
+}
+
+// rowLimit 返回列表每批显示的条数，0 表示不限
+func rowLimit(p fyne.Preferences) int {
+	n := p.Int(prefRowLimit)
+	if n < 0 || n > maxRowLimit {
+		return 0
+	}
+	return n
+}
+
+// composerLines 返回撰写模式输入框的行数，0 表示不使用撰写模式
+func composerLines(p fyne.Preferences) int {
+	n := p.Int(prefComposerLines)
+	if n < 0 || n > maxComposerLines {
+		return 0
+	}
+	return n
+}
+
+// wipThresholds 返回进行中条数的提醒阈值与上限，0 表示不提示，负数按 0 处理
+func wipThresholds(p fyne.Preferences) (warn, limit int) {
+	return max(p.Int(prefWIPWarn), 0), max(p.Int(prefWIPLimit), 0)
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/driver/desktop"
+)
+
+var quickAddWin fyne.Window
+
+// showQuickAdd 弹出无边框的快速添加框，不打开主窗口
+// Fyne 无法获取托盘图标位置，因此始终居中显示在主屏幕上
+func showQuickAdd(a fyne.App, add func(string) error) {
+	if quickAddWin != nil {
+		quickAddWin.RequestFocus()
+		return
+	}
+
+	var w fyne.Window
+	if drv, ok := a.Driver().(desktop.Driver); ok {
+		w = drv.CreateSplashWindow()
+	} else {
+		w = a.NewWindow("快速添加")
+	}
+	closeWin := func() {
+		if quickAddWin == w {
+			quickAddWin = nil
+			w.Close()
+		}
+	}
+
+	entry := newEscEntry()
+	entry.SetPlaceHolder("快速添加待办，回车确认，Esc 取消")
+	entry.Validator = func(text string) error {
+		if text == "" {
+			return nil
+		}
+		_, err := newTodo(a.Preferences(), text, time.Now())
+		return err
+	}
+	entry.onEscape = closeWin
+	entry.onFocusLost = closeWin
+	entry.OnSubmitted = func(text string) {
+		err := add(text)
+		if err == errEmptyTodo {
+			closeWin()
+			return
+		}
+		if err != nil {
+			entry.SetValidationError(err)
+			return
+		}
+		closeWin()
+	}
+
+	w.SetContent(entry)
+	w.Resize(fyne.NewSize(320, entry.MinSize().Height))
+	w.CenterOnScreen()
+	quickAddWin = w
+	w.Show()
+	w.Canvas().Focus(entry)
+}
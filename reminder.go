@@ -0,0 +1,104 @@
+package main
+
+import (
+	"log/slog"
+	"time"
+
+	"fyne.io/fyne/v2"
+)
+
+// remindOffsets 可选的提前提醒时间，单位分钟
+var remindOffsets = []struct {
+	minutes int
+	label   string
+}{
+	{0, "准时"},
+	{15, "提前 15 分钟"},
+	{60, "提前 1 小时"},
+	{24 * 60, "提前 1 天"},
+}
+
+// remindLabel 提前提醒分钟数的显示文字，不在预设中的按分钟显示
+func remindLabel(minutes int) string {
+	for _, o := range remindOffsets {
+		if o.minutes == minutes {
+			return o.label
+		}
+	}
+	return "提前 " + time.Duration(minutes*int(time.Minute)).String()
+}
+
+// remindBefore 待办的提前提醒分钟数，未单独设置时使用全局默认值
+func (t Todo) remindBefore(p fyne.Preferences) int {
+	if t.RemindBefore != nil {
+		return *t.RemindBefore
+	}
+	return p.IntWithFallback(prefRemindBefore, 0)
+}
+
+// remindAt 提醒时间为截止时间减去提前量；没有截止时间或已完成时不提醒
+func (t Todo) remindAt(p fyne.Preferences) (time.Time, bool) {
+	if t.Due == nil || t.Done {
+		return time.Time{}, false
+	}
+	return t.Due.Add(-time.Duration(t.remindBefore(p)) * time.Minute), true
+}
+
+// 提醒检查间隔的默认值与范围，单位秒
+const (
+	defaultRemindInterval = 60
+	minRemindInterval     = 5
+	maxRemindInterval     = 3600
+)
+
+// startReminders 在主线程检查 (上次检查, 现在] 之间到点的待办并发送通知，
+// 然后睡到最近的下一个提醒时间，但不超过偏好设置的检查间隔。
+// 从启动时刻开始计算，程序未运行期间错过的提醒不会补发。
+// 返回的函数在待办变化后调用，立即重新计算下次唤醒时间
+func startReminders(a fyne.App, list func() []Todo) func() {
+	last := time.Now()
+	wake := make(chan struct{}, 1)
+	check := func() time.Duration {
+		now := time.Now()
+		p := a.Preferences()
+		wait := time.Duration(p.IntWithFallback(prefRemindInterval, defaultRemindInterval)) * time.Second
+		for _, t := range list() {
+			at, ok := t.remindAt(p)
+			if !ok {
+				continue
+			}
+			if at.After(now) {
+				wait = min(wait, at.Sub(now))
+				continue
+			}
+			if !at.After(last) {
+				continue
+			}
+			slog.Debug("reminder sent", "text", t.Text, "due", *t.Due)
+			a.SendNotification(fyne.NewNotification("待办提醒", t.Text+"\n截止 "+formatDue(*t.Due, now)))
+			if t.reminderSound(p) == soundChime {
+				playChime()
+			}
+		}
+		last = now
+		return max(wait, time.Second)
+	}
+	go func() {
+		for {
+			next := make(chan time.Duration, 1)
+			fyne.Do(func() { next <- check() })
+			timer := time.NewTimer(<-next)
+			select {
+			case <-timer.C:
+			case <-wake:
+				timer.Stop()
+			}
+		}
+	}()
+	return func() {
+		select {
+		case wake <- struct{}{}:
+		default:
+		}
+	}
+}
@@ -0,0 +1,114 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+// showReorderDialog 在副本上调整顺序，点击确定后以调整后的 ID 顺序调用 onApply
+func showReorderDialog(win fyne.Window, todos []Todo, onApply func(ids []string)) {
+	work := append([]Todo(nil), todos...)
+	rows := container.NewVBox()
+
+	var rebuild func()
+	move := func(from, to int) {
+		if to < 0 || to >= len(work) {
+			return
+		}
+		work[from], work[to] = work[to], work[from]
+		rebuild()
+	}
+	rebuild = func() {
+		rows.Objects = nil
+		for i, t := range work {
+			index := i
+			up := widget.NewButtonWithIcon("", theme.MoveUpIcon(), func() { move(index, index-1) })
+			down := widget.NewButtonWithIcon("", theme.MoveDownIcon(), func() { move(index, index+1) })
+			top := widget.NewButtonWithIcon("", theme.MenuDropUpIcon(), func() {
+				item := work[index]
+				copy(work[1:index+1], work[:index])
+				work[0] = item
+				rebuild()
+			})
+			up.Importance = widget.LowImportance
+			down.Importance = widget.LowImportance
+			top.Importance = widget.LowImportance
+			if index == 0 {
+				up.Disable()
+				top.Disable()
+			}
+			if index == len(work)-1 {
+				down.Disable()
+			}
+			label := widget.NewLabel(t.Text)
+			label.Truncation = fyne.TextTruncateEllipsis
+			rows.Add(container.NewBorder(nil, nil, nil, container.NewHBox(top, up, down), label))
+		}
+		rows.Refresh()
+	}
+	rebuild()
+
+	d := dialog.NewCustomConfirm("整理排序", "确定", "取消", container.NewVScroll(rows), func(ok bool) {
+		if ok {
+			ids := make([]string, len(work))
+			for i, t := range work {
+				ids[i] = t.ID
+			}
+			onApply(ids)
+		}
+	}, win)
+	d.Resize(fyne.NewSize(win.Canvas().Size().Width*0.9, win.Canvas().Size().Height*0.9))
+	d.Show()
+}
+
+// applyOrder 按 ids 的顺序排列当前的 todos：对话框打开后新增的条目按原顺序放在最后，
+// 已被删除的 ID 忽略，条目内容取当前列表中的
+func applyOrder(todos []Todo, ids []string) []Todo {
+	pos := make(map[string]int, len(ids))
+	for i, id := range ids {
+		pos[id] = i
+	}
+	ordered := slices.Clone(todos)
+	slices.SortStableFunc(ordered, func(x, y Todo) int {
+		px, okX := pos[x.ID]
+		py, okY := pos[y.ID]
+		switch {
+		case okX && okY:
+			return px - py
+		case okX:
+			return -1
+		case okY:
+			return 1
+		}
+		return 0
+	})
+	return ordered
+}
+
+// showDependencyDialog 从 candidates 中选择一条作为依赖，确定后以其 ID 调用 onPick
+func showDependencyDialog(win fyne.Window, candidates []Todo, onPick func(id string)) {
+	if len(candidates) == 0 {
+		showTemporaryPopUp(win.Canvas(), "没有可以依赖的待办", 2)
+		return
+	}
+	// 选项带上序号，文字相同的待办也能区分，选择后按下标取回
+	var labels []string
+	for i, t := range candidates {
+		labels = append(labels, fmt.Sprintf("%d. %s", i+1, truncateRunes(strings.ReplaceAll(t.Text, "\n", " "), 40)))
+	}
+	choice := widget.NewSelect(labels, nil)
+	dialog.ShowForm("依赖于", "确定", "取消", []*widget.FormItem{
+		widget.NewFormItem("完成后才能开始", choice),
+	}, func(ok bool) {
+		if ok && choice.SelectedIndex() >= 0 {
+			onPick(candidates[choice.SelectedIndex()].ID)
+		}
+	}, win)
+}
@@ -0,0 +1,33 @@
+package main
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestApplyOrder(t *testing.T) {
+	todos := func(ids ...string) []Todo {
+		var out []Todo
+		for _, id := range ids {
+			out = append(out, Todo{ID: id, Text: "item " + id})
+		}
+		return out
+	}
+	for _, c := range []struct {
+		name        string
+		current     []Todo
+		order, want []string
+	}{
+		{"reordered", todos("a", "b", "c"), []string{"c", "a", "b"}, []string{"c", "a", "b"}},
+		{"added while open", todos("a", "b", "c", "d"), []string{"b", "a", "c"}, []string{"b", "a", "c", "d"}},
+		{"deleted while open", todos("a", "c"), []string{"c", "b", "a"}, []string{"c", "a"}},
+	} {
+		var got []string
+		for _, t := range applyOrder(c.current, c.order) {
+			got = append(got, t.ID)
+		}
+		if !slices.Equal(got, c.want) {
+			t.Errorf("%s: got %v, want %v", c.name, got, c.want)
+		}
+	}
+}
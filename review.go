@@ -0,0 +1,104 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+)
+
+// 回顾久未处理待办的频率
+const (
+	reviewOff     = ""
+	reviewStartup = "startup"
+	reviewDaily   = cadenceDaily
+	reviewWeekly  = cadenceWeekly
+
+	defaultReviewDays = 30
+	maxReviewDays     = 3650
+)
+
+var reviewCadences = []struct{ cadence, label string }{
+	{reviewOff, "关闭"},
+	{reviewStartup, "每次启动时"},
+	{reviewDaily, "每天"},
+	{reviewWeekly, "每周"},
+}
+
+// reviewDays 返回需要回顾的未改动天数，超出范围时使用默认值
+func reviewDays(p fyne.Preferences) int {
+	n := p.IntWithFallback(prefReviewDays, defaultReviewDays)
+	if n < 1 || n > maxReviewDays {
+		return defaultReviewDays
+	}
+	return n
+}
+
+// staleTodos 返回超过 days 天没有改动的未完成待办，最久未动的在前；
+// 推迟到以后开始的不算在内
+func staleTodos(todos []Todo, days int, now time.Time) []Todo {
+	var stale []Todo
+	for _, t := range todos {
+		if !t.Done && !t.deferred(now) && now.Sub(t.UpdatedAt) >= time.Duration(days)*24*time.Hour {
+			stale = append(stale, t)
+		}
+	}
+	slices.SortStableFunc(stale, func(a, b Todo) int { return a.UpdatedAt.Compare(b.UpdatedAt) })
+	return stale
+}
+
+// startReview 按频率检查是否需要回顾：选择启动时的只在启动时调用一次 run，
+// 每天、每周的与自动导出一样每小时检查距上次回顾是否已满一个周期
+func startReview(p fyne.Preferences, run func()) {
+	startHourly(func(startup bool) {
+		cadence, now := p.String(prefReviewCadence), time.Now()
+		switch {
+		case cadence == reviewStartup && startup:
+			run()
+		case periodicDue(p, prefReviewLast, cadencePeriod(cadence), now):
+			run()
+			markPeriodic(p, prefReviewLast, now)
+		}
+	})
+}
+
+// showReviewDialog 列出久未处理的待办，每条可以保留、完成或删除，处理过的从列表中移除
+func showReviewDialog(win fyne.Window, items []Todo, now time.Time, onKeep, onComplete, onDelete func(id string)) {
+	rows := container.NewVBox()
+	var d *dialog.CustomDialog
+	for _, t := range items {
+		label := widget.NewLabel(t.Text)
+		label.Wrapping = fyne.TextWrapWord
+		age := widget.NewLabel(fmt.Sprintf("%d 天未改动", daysBetween(t.UpdatedAt.In(time.Local), now)))
+		age.Importance = widget.LowImportance
+		var row *fyne.Container
+		act := func(action func(id string)) func() {
+			id := t.ID
+			return func() {
+				rows.Remove(row)
+				action(id)
+				if len(rows.Objects) == 0 {
+					d.Hide()
+				}
+			}
+		}
+		keep := widget.NewButton("保留", act(onKeep))
+		complete := widget.NewButton("完成", act(onComplete))
+		del := widget.NewButton("删除", act(onDelete))
+		del.Importance = widget.DangerImportance
+		row = container.NewBorder(nil, nil, nil, container.NewHBox(keep, complete, del),
+			container.NewVBox(label, age))
+		rows.Add(row)
+	}
+	summary := widget.NewLabel(fmt.Sprintf("%d 条待办很久没有改动，保留会重新开始计算", len(items)))
+	summary.Wrapping = fyne.TextWrapWord
+	content := container.NewBorder(container.NewVBox(summary, widget.NewSeparator()), nil, nil, nil,
+		container.NewVScroll(rows))
+	d = dialog.NewCustom("需要回顾", "稍后", content, win)
+	d.Resize(fyne.NewSize(win.Canvas().Size().Width*0.9, win.Canvas().Size().Height*0.8))
+	d.Show()
+}
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"time"
+
+	"fyne.io/fyne/v2"
+)
+
+// 定时任务（自动导出、回顾）共用的频率
+const (
+	cadenceDaily  = "daily"
+	cadenceWeekly = "weekly"
+)
+
+// cadencePeriod 频率对应的间隔，不是定时频率（关闭、启动时、退出时等）时返回 0
+func cadencePeriod(cadence string) time.Duration {
+	switch cadence {
+	case cadenceDaily:
+		return 24 * time.Hour
+	case cadenceWeekly:
+		return 7 * 24 * time.Hour
+	}
+	return 0
+}
+
+// periodicDue 判断距上次运行是否已满 period，上次时间以 RFC3339 保存在 lastKey 中
+func periodicDue(p fyne.Preferences, lastKey string, period time.Duration, now time.Time) bool {
+	last, _ := time.Parse(time.RFC3339, p.String(lastKey))
+	return period > 0 && now.Sub(last) >= period
+}
+
+// markPeriodic 记录本次运行的时间；运行成功后才调用，失败的在下次检查时重试
+func markPeriodic(p fyne.Preferences, lastKey string, now time.Time) {
+	p.SetString(lastKey, now.Format(time.RFC3339))
+}
+
+// startHourly 启动时与之后每小时在主线程调用 run，startup 表示是否为启动时的一次
+func startHourly(run func(startup bool)) {
+	go func() {
+		fyne.Do(func() { run(true) })
+		for range time.Tick(time.Hour) {
+			fyne.Do(func() { run(false) })
+		}
+	}()
+}
@@ -0,0 +1,867 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+)
+
+var settingsWin fyne.Window
+
+// showSettings 打开设置窗口，修改即时写入偏好设置并调用 onChange 应用到界面
+func showSettings(a fyne.App, onChange func()) {
+	if settingsWin != nil {
+		settingsWin.Show()
+		settingsWin.RequestFocus()
+		return
+	}
+	p := a.Preferences()
+
+	normalize := widget.NewCheck("合并多余空白", func(on bool) {
+		p.SetBool(prefNormalizeSpace, on)
+	})
+	normalize.SetChecked(p.BoolWithFallback(prefNormalizeSpace, true))
+
+	softBreak := widget.NewCheck("Shift+回车换行", nil)
+	softBreak.Checked = p.Bool(prefSoftBreak)
+	softBreak.OnChanged = func(on bool) {
+		p.SetBool(prefSoftBreak, on)
+		onChange()
+	}
+
+	copyPopup := widget.NewCheck("复制后显示提示", func(on bool) {
+		p.SetBool(prefCopyPopup, on)
+	})
+	copyPopup.SetChecked(p.BoolWithFallback(prefCopyPopup, true))
+	var copyLabels []string
+	for _, m := range copyModes {
+		copyLabels = append(copyLabels, m.label)
+	}
+	copyMode := widget.NewSelect(copyLabels, nil)
+	for i, m := range copyModes {
+		if m.mode == p.String(prefCopyMode) {
+			copyMode.SetSelectedIndex(i)
+		}
+	}
+	copyMode.OnChanged = func(string) {
+		p.SetString(prefCopyMode, copyModes[copyMode.SelectedIndex()].mode)
+	}
+
+	smart := widget.NewCheck("识别 !优先级 @日期 #标签", func(on bool) {
+		p.SetBool(prefSmartTokens, on)
+	})
+	smart.SetChecked(p.Bool(prefSmartTokens))
+
+	advance := widget.NewCheck("用空格完成后跳到下一条", func(on bool) {
+		p.SetBool(prefCompleteAdvance, on)
+	})
+	advance.SetChecked(p.BoolWithFallback(prefCompleteAdvance, true))
+
+	doubleTap := widget.NewCheck("双击文字编辑", func(on bool) {
+		p.SetBool(prefDoubleTapEdit, on)
+	})
+	doubleTap.SetChecked(p.BoolWithFallback(prefDoubleTapEdit, true))
+
+	keepDone := widget.NewCheck("完成后保留在列表中，取消勾选可恢复", func(on bool) {
+		p.SetBool(prefKeepDone, on)
+	})
+	keepDone.SetChecked(p.Bool(prefKeepDone))
+	if _, text := store.(*textStore); text {
+		keepDone.SetText("完成后保留在列表中（txt 格式无法保存完成状态）")
+		keepDone.Disable()
+	}
+
+	confirmQuit := widget.NewCheck("退出前确认", func(on bool) {
+		p.SetBool(prefConfirmQuit, on)
+	})
+	confirmQuit.SetChecked(p.Bool(prefConfirmQuit))
+	allWorkspaces := widget.NewCheck("显示在所有工作区（Linux X11，需要 wmctrl）", func(on bool) {
+		p.SetBool(prefAllWorkspaces, on)
+		setSticky(winTitle, on)
+	})
+	allWorkspaces.SetChecked(p.Bool(prefAllWorkspaces))
+	linkTitlesCheck := widget.NewCheck("获取链接的网页标题并代替链接显示（会访问网络）", func(on bool) {
+		p.SetBool(prefLinkTitles, on)
+		onChange()
+	})
+	linkTitlesCheck.SetChecked(p.Bool(prefLinkTitles))
+	normalizeTagsCheck := widget.NewCheck("标签统一为小写（已有数据可在列表菜单中规范化）", func(on bool) {
+		p.SetBool(prefNormalizeTags, on)
+	})
+	normalizeTagsCheck.SetChecked(p.Bool(prefNormalizeTags))
+	completeSound := widget.NewCheck("完成时播放提示音", func(on bool) {
+		p.SetBool(prefCompleteSound, on)
+	})
+	completeSound.SetChecked(p.Bool(prefCompleteSound))
+	snoozeToday := widget.NewCheck("顺延到星期几时，今天正是那天则顺延到今天", func(on bool) {
+		p.SetBool(prefSnoozeToday, on)
+	})
+	snoozeToday.SetChecked(p.Bool(prefSnoozeToday))
+	outcomePrompt := widget.NewCheck("完成时询问结果备注", func(on bool) {
+		p.SetBool(prefOutcomePrompt, on)
+	})
+	outcomePrompt.SetChecked(p.Bool(prefOutcomePrompt))
+	quitHides := widget.NewCheck("托盘中的“退出”改为隐藏窗口", func(on bool) {
+		p.SetBool(prefQuitHides, on)
+		onChange()
+	})
+	quitHides.SetChecked(p.Bool(prefQuitHides))
+	// quitBtn 真正退出程序，托盘中的"退出"改为隐藏窗口后从这里退出
+	quitBtn := widget.NewButton("退出程序", func() {
+		dialog.ShowConfirm("退出", "确定要退出待办事项吗？", func(ok bool) {
+			if ok {
+				a.Quit()
+			}
+		}, settingsWin)
+	})
+	quitBtn.Importance = widget.DangerImportance
+
+	maxLenEntry := widget.NewEntry()
+	maxLenEntry.SetText(strconv.Itoa(maxTextLen(p)))
+	maxLenEntry.Validator = func(s string) error {
+		n, err := strconv.Atoi(s)
+		if err != nil || n < 1 || n > maxMaxLen {
+			return fmt.Errorf("请输入 1-%d 之间的整数", maxMaxLen)
+		}
+		return nil
+	}
+	maxLenEntry.OnChanged = func(s string) {
+		if maxLenEntry.Validate() != nil {
+			return
+		}
+		n, _ := strconv.Atoi(s)
+		p.SetInt(prefMaxLen, n)
+		onChange()
+	}
+
+	maxItemsEntry := widget.NewEntry()
+	maxItemsEntry.SetText(strconv.Itoa(maxItemCount(p)))
+	maxItemsEntry.Validator = func(s string) error {
+		n, err := strconv.Atoi(s)
+		if err != nil || n < 0 || n > maxMaxItems {
+			return fmt.Errorf("请输入 0-%d 之间的整数，0 表示不限", maxMaxItems)
+		}
+		return nil
+	}
+	maxItemsEntry.OnChanged = func(s string) {
+		if maxItemsEntry.Validate() != nil {
+			return
+		}
+		n, _ := strconv.Atoi(s)
+		p.SetInt(prefMaxItems, n)
+	}
+
+	var actionLabels, enabledLabels []string
+	for _, act := range trayActions {
+		actionLabels = append(actionLabels, act.Label)
+	}
+	for _, act := range enabledTrayActions(p) {
+		enabledLabels = append(enabledLabels, act.Label)
+	}
+	trayGroup := widget.NewCheckGroup(actionLabels, func(selected []string) {
+		ids := []string{}
+		for _, act := range trayActions {
+			for _, label := range selected {
+				if label == act.Label {
+					ids = append(ids, act.ID)
+				}
+			}
+		}
+		p.SetStringList(prefTrayActions, ids)
+		onChange()
+	})
+	trayGroup.Selected = enabledLabels
+
+	saveModes := []struct{ mode, label string }{
+		{saveImmediate, "立即保存"},
+		{saveDebounced, "延迟合并保存"},
+		{saveOnQuit, "仅退出时保存"},
+		{saveBackground, "后台保存（适合网络磁盘）"},
+	}
+	var saveLabels []string
+	for _, m := range saveModes {
+		saveLabels = append(saveLabels, m.label)
+	}
+	saveSelect := widget.NewSelect(saveLabels, nil)
+	for i, m := range saveModes {
+		if m.mode == p.StringWithFallback(prefSaveMode, saveImmediate) {
+			saveSelect.SetSelectedIndex(i)
+		}
+	}
+	saveSelect.OnChanged = func(string) {
+		m := saveModes[saveSelect.SelectedIndex()]
+		p.SetString(prefSaveMode, m.mode)
+		onChange()
+		if m.mode == saveOnQuit {
+			dialog.ShowInformation("仅退出时保存", "修改只在正常退出时写入磁盘，程序崩溃或断电会丢失本次运行的全部修改。", settingsWin)
+		}
+	}
+
+	dataPath, _ := filepath.Abs(storePath())
+	pathLabel := widget.NewLabel(dataPath)
+	pathLabel.Wrapping = fyne.TextWrapBreak
+
+	var remindLabels []string
+	for _, o := range remindOffsets {
+		remindLabels = append(remindLabels, o.label)
+	}
+	remindSelect := widget.NewSelect(remindLabels, nil)
+	for i, o := range remindOffsets {
+		if o.minutes == p.Int(prefRemindBefore) {
+			remindSelect.SetSelectedIndex(i)
+		}
+	}
+	remindSelect.OnChanged = func(string) {
+		p.SetInt(prefRemindBefore, remindOffsets[remindSelect.SelectedIndex()].minutes)
+	}
+
+	var soundLabels []string
+	for _, o := range soundOptions {
+		soundLabels = append(soundLabels, o.label)
+	}
+	soundSelect := widget.NewSelect(soundLabels, nil)
+	soundSelect.SetSelected(soundLabel(p.StringWithFallback(prefRemindSound, soundSystem)))
+	soundSelect.OnChanged = func(string) {
+		p.SetString(prefRemindSound, soundOptions[soundSelect.SelectedIndex()].sound)
+	}
+	soundHelp := widget.NewLabel("通知本身的声音由系统决定，“静音”只是不再播放提示音")
+	soundHelp.Wrapping = fyne.TextWrapWord
+	soundHelp.Importance = widget.LowImportance
+
+	captureEntry := widget.NewEntry()
+	captureEntry.SetPlaceHolder("例如 {date} {text} #日记")
+	captureEntry.SetText(p.String(prefCaptureTemplate))
+	captureEntry.OnChanged = func(s string) {
+		p.SetString(prefCaptureTemplate, s)
+	}
+	captureHelpLabel := widget.NewLabel(captureHelp)
+	captureHelpLabel.Wrapping = fyne.TextWrapWord
+	captureHelpLabel.Importance = widget.LowImportance
+
+	var filterLabels []string
+	for _, m := range filterModes {
+		filterLabels = append(filterLabels, m.label)
+	}
+	filterMode := widget.NewRadioGroup(filterLabels, nil)
+	filterMode.SetSelected(filterModes[0].label)
+	for _, m := range filterModes {
+		if m.mode == p.String(prefFilterMode) {
+			filterMode.SetSelected(m.label)
+		}
+	}
+	filterMode.Required = true
+	filterMode.OnChanged = func(label string) {
+		for _, m := range filterModes {
+			if m.label == label {
+				p.SetString(prefFilterMode, m.mode)
+			}
+		}
+		onChange()
+	}
+
+	hookEntry := widget.NewEntry()
+	hookEntry.SetPlaceHolder(`例如 notify-send 已完成 {text}`)
+	hookEntry.SetText(p.String(prefCompleteHook))
+	hookEntry.Validator = func(s string) error {
+		_, err := splitCommand(s)
+		return err
+	}
+	hookEntry.OnChanged = func(s string) {
+		if hookEntry.Validate() == nil {
+			p.SetString(prefCompleteHook, s)
+		}
+	}
+	hookHelp := widget.NewLabel("不经过 shell 直接运行，可用 {text} {priority} {due} {tags} {color} {created}")
+	hookHelp.Wrapping = fyne.TextWrapWord
+	hookHelp.Importance = widget.LowImportance
+
+	intervalEntry := widget.NewEntry()
+	intervalEntry.SetText(strconv.Itoa(p.IntWithFallback(prefRemindInterval, defaultRemindInterval)))
+	intervalEntry.Validator = func(s string) error {
+		n, err := strconv.Atoi(s)
+		if err != nil || n < minRemindInterval || n > maxRemindInterval {
+			return fmt.Errorf("请输入 %d-%d 之间的秒数", minRemindInterval, maxRemindInterval)
+		}
+		return nil
+	}
+	intervalEntry.OnChanged = func(s string) {
+		if intervalEntry.Validate() != nil {
+			return
+		}
+		n, _ := strconv.Atoi(s)
+		p.SetInt(prefRemindInterval, n)
+	}
+
+	focusOpen := widget.NewRadioGroup([]string{"输入框", "列表"}, func(s string) {
+		p.SetBool(prefFocusList, s == "列表")
+	})
+	focusOpen.Horizontal = true
+	focusOpen.Required = true
+	focusOpen.Selected = "输入框"
+	if p.Bool(prefFocusList) {
+		focusOpen.Selected = "列表"
+	}
+
+	swipeActions := []struct{ action, label string }{
+		{swipeNone, "无"},
+		{swipeComplete, "完成"},
+		{swipeDelete, "删除"},
+	}
+	var swipeLabels []string
+	for _, s := range swipeActions {
+		swipeLabels = append(swipeLabels, s.label)
+	}
+	swipeSelect := func(key string) *widget.Select {
+		sel := widget.NewSelect(swipeLabels, nil)
+		sel.SetSelectedIndex(0)
+		for i, s := range swipeActions {
+			if s.action == p.String(key) {
+				sel.SetSelectedIndex(i)
+			}
+		}
+		sel.OnChanged = func(string) {
+			p.SetString(key, swipeActions[sel.SelectedIndex()].action)
+			onChange()
+		}
+		return sel
+	}
+
+	var reviewLabels []string
+	for _, c := range reviewCadences {
+		reviewLabels = append(reviewLabels, c.label)
+	}
+	reviewSelect := widget.NewSelect(reviewLabels, nil)
+	reviewSelect.SetSelectedIndex(0)
+	for i, c := range reviewCadences {
+		if c.cadence == p.String(prefReviewCadence) {
+			reviewSelect.SetSelectedIndex(i)
+		}
+	}
+	reviewSelect.OnChanged = func(string) {
+		p.SetString(prefReviewCadence, reviewCadences[reviewSelect.SelectedIndex()].cadence)
+	}
+	reviewDaysEntry := widget.NewEntry()
+	reviewDaysEntry.SetText(strconv.Itoa(reviewDays(p)))
+	reviewDaysEntry.Validator = func(s string) error {
+		n, err := strconv.Atoi(s)
+		if err != nil || n < 1 || n > maxReviewDays {
+			return fmt.Errorf("请输入 1-%d 之间的天数", maxReviewDays)
+		}
+		return nil
+	}
+	reviewDaysEntry.OnChanged = func(s string) {
+		if reviewDaysEntry.Validate() != nil {
+			return
+		}
+		n, _ := strconv.Atoi(s)
+		p.SetInt(prefReviewDays, n)
+	}
+
+	behavior := widget.NewForm(
+		widget.NewFormItem("输入", container.NewVBox(normalize, softBreak)),
+		widget.NewFormItem("标签", normalizeTagsCheck),
+		widget.NewFormItem("智能识别", smart),
+		widget.NewFormItem("添加模板", container.NewVBox(captureEntry, captureHelpLabel)),
+		widget.NewFormItem("连续完成", advance),
+		widget.NewFormItem("多个筛选", filterMode),
+		widget.NewFormItem("编辑", doubleTap),
+		widget.NewFormItem("打开时聚焦", focusOpen),
+		widget.NewFormItem("工作区", allWorkspaces),
+		widget.NewFormItem("向左滑动", swipeSelect(prefSwipeLeft)),
+		widget.NewFormItem("向右滑动", swipeSelect(prefSwipeRight)),
+		widget.NewFormItem("复制", container.NewVBox(copyMode, copyPopup)),
+		widget.NewFormItem("已完成", container.NewVBox(keepDone, outcomePrompt, completeSound)),
+		widget.NewFormItem("退出", container.NewVBox(confirmQuit, quitHides, quitBtn)),
+		widget.NewFormItem("完成时运行", container.NewVBox(hookEntry, hookHelp)),
+		widget.NewFormItem("最大字数", maxLenEntry),
+		widget.NewFormItem("最多条数", maxItemsEntry),
+		widget.NewFormItem("回顾久未处理", reviewSelect),
+		widget.NewFormItem("超过天数未改动", reviewDaysEntry),
+	)
+	reminders := widget.NewForm(
+		widget.NewFormItem("默认提醒", remindSelect),
+		widget.NewFormItem("顺延", snoozeToday),
+		widget.NewFormItem("提醒声音", container.NewVBox(soundSelect, soundHelp)),
+		widget.NewFormItem("检查间隔（秒）", intervalEntry),
+	)
+	iconEntry := widget.NewEntry()
+	iconEntry.SetPlaceHolder("留空使用内置图标")
+	iconEntry.SetText(p.String(prefAppIcon))
+	iconEntry.OnSubmitted = func(path string) {
+		p.SetString(prefAppIcon, path)
+		onChange()
+	}
+	iconBrowse := widget.NewButton("选择…", func() {
+		dialog.ShowFileOpen(func(r fyne.URIReadCloser, err error) {
+			if err != nil || r == nil {
+				return
+			}
+			r.Close()
+			iconEntry.SetText(r.URI().Path())
+			iconEntry.OnSubmitted(iconEntry.Text)
+		}, settingsWin)
+	})
+
+	linesEntry := widget.NewEntry()
+	linesEntry.SetText(strconv.Itoa(rowMaxLines(p)))
+	linesEntry.Validator = func(s string) error {
+		n, err := strconv.Atoi(s)
+		if err != nil || n < 0 || n > maxRowLines {
+			return fmt.Errorf("请输入 0-%d 之间的整数，0 表示完整显示", maxRowLines)
+		}
+		return nil
+	}
+	linesEntry.OnChanged = func(s string) {
+		if linesEntry.Validate() != nil {
+			return
+		}
+		n, _ := strconv.Atoi(s)
+		p.SetInt(prefRowMaxLines, n)
+		onChange()
+	}
+
+	composerEntry := widget.NewEntry()
+	composerEntry.SetText(strconv.Itoa(composerLines(p)))
+	composerEntry.Validator = func(s string) error {
+		n, err := strconv.Atoi(s)
+		if err != nil || n < 0 || n > maxComposerLines {
+			return fmt.Errorf("请输入 0-%d 之间的整数，0 表示单行输入框", maxComposerLines)
+		}
+		return nil
+	}
+	composerEntry.OnChanged = func(s string) {
+		if composerEntry.Validate() != nil {
+			return
+		}
+		n, _ := strconv.Atoi(s)
+		p.SetInt(prefComposerLines, n)
+		onChange()
+	}
+
+	inputPos := widget.NewRadioGroup([]string{"顶部", "底部"}, func(s string) {
+		p.SetBool(prefInputTop, s == "顶部")
+		onChange()
+	})
+	inputPos.Horizontal = true
+	inputPos.Required = true
+	inputPos.Selected = "底部"
+	if p.Bool(prefInputTop) {
+		inputPos.Selected = "顶部"
+	}
+
+	newestFirst := widget.NewCheck("最新添加的显示在最上面", nil)
+	newestFirst.Checked = p.Bool(prefNewestFirst)
+	newestFirst.OnChanged = func(on bool) {
+		p.SetBool(prefNewestFirst, on)
+		onChange()
+	}
+
+	statusBar := widget.NewCheck("显示计数与快捷筛选", nil)
+	statusBar.Checked = p.BoolWithFallback(prefStatusBar, true)
+	statusBar.OnChanged = func(on bool) {
+		p.SetBool(prefStatusBar, on)
+		onChange()
+	}
+
+	var accentLabels []string
+	for _, ac := range accentPresets {
+		accentLabels = append(accentLabels, ac.Name)
+	}
+	accentLabels = append(accentLabels, "自定义")
+	accentEntry := widget.NewEntry()
+	accentEntry.SetPlaceHolder("#RRGGBB")
+	accentEntry.SetText(p.String(prefAccentColor))
+	accentEntry.Validator = func(s string) error {
+		if _, ok := parseHexColor(s); !ok {
+			return errors.New("请输入 #RGB 或 #RRGGBB 格式的颜色")
+		}
+		return nil
+	}
+	accentEntry.OnChanged = func(s string) {
+		if accentEntry.Validate() != nil {
+			return
+		}
+		p.SetString(prefAccentColor, s)
+		onChange()
+	}
+	accentSelect := widget.NewSelect(accentLabels, nil)
+	accentSelect.SetSelectedIndex(0)
+	for i, ac := range accentPresets {
+		if ac.Name == p.String(prefAccent) {
+			accentSelect.SetSelectedIndex(i)
+		}
+	}
+	if p.String(prefAccent) == accentCustom {
+		accentSelect.SetSelectedIndex(len(accentPresets))
+	} else {
+		accentEntry.Hide()
+	}
+	accentSelect.OnChanged = func(string) {
+		i := accentSelect.SelectedIndex()
+		if i == len(accentPresets) {
+			p.SetString(prefAccent, accentCustom)
+			accentEntry.Show()
+		} else {
+			p.SetString(prefAccent, accentPresets[i].Name)
+			accentEntry.Hide()
+		}
+		onChange()
+	}
+
+	var variantLabels []string
+	for _, v := range themeVariants {
+		variantLabels = append(variantLabels, v.label)
+	}
+	variantSelect := widget.NewSelect(variantLabels, nil)
+	variantSelect.SetSelectedIndex(0)
+	for i, v := range themeVariants {
+		if v.variant == p.String(prefThemeVariant) {
+			variantSelect.SetSelectedIndex(i)
+		}
+	}
+	variantSelect.OnChanged = func(string) {
+		p.SetString(prefThemeVariant, themeVariants[variantSelect.SelectedIndex()].variant)
+		onChange()
+	}
+
+	separators := []struct{ style, label string }{
+		{separatorLine, "实线"},
+		{separatorDotted, "浅色点线"},
+		{separatorNone, "无"},
+	}
+	var separatorLabels []string
+	for _, s := range separators {
+		separatorLabels = append(separatorLabels, s.label)
+	}
+	separatorSelect := widget.NewSelect(separatorLabels, nil)
+	for i, s := range separators {
+		if s.style == p.String(prefSeparator) {
+			separatorSelect.SetSelectedIndex(i)
+		}
+	}
+	separatorSelect.OnChanged = func(string) {
+		p.SetString(prefSeparator, separators[separatorSelect.SelectedIndex()].style)
+		onChange()
+	}
+
+	var densityLabels []string
+	for _, d := range densities {
+		densityLabels = append(densityLabels, d.label)
+	}
+	densitySelect := widget.NewSelect(densityLabels, nil)
+	for i, d := range densities {
+		if d.density == p.StringWithFallback(prefDensity, densityNormal) {
+			densitySelect.SetSelectedIndex(i)
+		}
+	}
+	densitySelect.OnChanged = func(string) {
+		p.SetString(prefDensity, densities[densitySelect.SelectedIndex()].density)
+		onChange()
+	}
+
+	var rowNumberLabels []string
+	for _, m := range rowNumberModes {
+		rowNumberLabels = append(rowNumberLabels, m.label)
+	}
+	rowNumbers := widget.NewSelect(rowNumberLabels, nil)
+	for i, m := range rowNumberModes {
+		if m.mode == p.String(prefRowNumbers) {
+			rowNumbers.SetSelectedIndex(i)
+		}
+	}
+	rowNumbers.OnChanged = func(string) {
+		p.SetString(prefRowNumbers, rowNumberModes[rowNumbers.SelectedIndex()].mode)
+		onChange()
+	}
+
+	emphases := []struct{ style, label string }{
+		{emphasisNone, "与其它条目相同"},
+		{emphasisBold, "加粗"},
+		{emphasisLarge, "加粗并放大"},
+	}
+	var emphasisLabels []string
+	for _, e := range emphases {
+		emphasisLabels = append(emphasisLabels, e.label)
+	}
+	emphasisSelect := widget.NewSelect(emphasisLabels, nil)
+	for i, e := range emphases {
+		if e.style == p.String(prefEmphasis) {
+			emphasisSelect.SetSelectedIndex(i)
+		}
+	}
+	emphasisSelect.OnChanged = func(string) {
+		p.SetString(prefEmphasis, emphases[emphasisSelect.SelectedIndex()].style)
+		onChange()
+	}
+
+	trayDelayEntry := widget.NewEntry()
+	trayDelayEntry.SetText(strconv.Itoa(p.Int(prefTrayDelay)))
+	trayDelayEntry.Validator = func(s string) error {
+		n, err := strconv.Atoi(s)
+		if err != nil || n < 0 || n > maxTrayDelay {
+			return fmt.Errorf("请输入 0-%d 之间的秒数，下次启动生效", maxTrayDelay)
+		}
+		return nil
+	}
+	trayDelayEntry.OnChanged = func(s string) {
+		if trayDelayEntry.Validate() != nil {
+			return
+		}
+		n, _ := strconv.Atoi(s)
+		p.SetInt(prefTrayDelay, n)
+	}
+
+	dirs := []struct{ dir, label string }{
+		{dirAuto, "按内容自动判断"},
+		{dirLTR, "从左到右"},
+		{dirRTL, "从右到左"},
+	}
+	var dirLabels []string
+	for _, d := range dirs {
+		dirLabels = append(dirLabels, d.label)
+	}
+	dirSelect := widget.NewSelect(dirLabels, nil)
+	for i, d := range dirs {
+		if d.dir == p.String(prefTextDir) {
+			dirSelect.SetSelectedIndex(i)
+		}
+	}
+	dirSelect.OnChanged = func(string) {
+		p.SetString(prefTextDir, dirs[dirSelect.SelectedIndex()].dir)
+		onChange()
+	}
+
+	trayLenEntry := widget.NewEntry()
+	trayLenEntry.SetText(strconv.Itoa(trayTextLen(p)))
+	trayLenEntry.Validator = func(s string) error {
+		n, err := strconv.Atoi(s)
+		if err != nil || n < minTrayTextLen || n > maxTrayTextLen {
+			return fmt.Errorf("请输入 %d-%d 之间的整数", minTrayTextLen, maxTrayTextLen)
+		}
+		return nil
+	}
+	trayLenEntry.OnChanged = func(s string) {
+		if trayLenEntry.Validate() != nil {
+			return
+		}
+		n, _ := strconv.Atoi(s)
+		p.SetInt(prefTrayTextLen, n)
+		onChange()
+	}
+
+	splitView := widget.NewCheck("窗口较宽时在右侧显示详情", nil)
+	splitView.Checked = p.Bool(prefSplitView)
+	splitView.OnChanged = func(on bool) {
+		p.SetBool(prefSplitView, on)
+		onChange()
+	}
+
+	rowLimitEntry := widget.NewEntry()
+	rowLimitEntry.SetText(strconv.Itoa(rowLimit(p)))
+	rowLimitEntry.Validator = func(s string) error {
+		n, err := strconv.Atoi(s)
+		if err != nil || n < 0 || n > maxRowLimit {
+			return fmt.Errorf("请输入 0-%d 之间的整数，0 表示全部显示", maxRowLimit)
+		}
+		return nil
+	}
+	rowLimitEntry.OnChanged = func(s string) {
+		if rowLimitEntry.Validate() != nil {
+			return
+		}
+		n, _ := strconv.Atoi(s)
+		p.SetInt(prefRowLimit, n)
+		onChange()
+	}
+
+	// wipEntry 进行中条数阈值的输入框，0 表示不提示
+	wipEntry := func(key string) *widget.Entry {
+		e := widget.NewEntry()
+		e.SetText(strconv.Itoa(max(p.Int(key), 0)))
+		e.Validator = func(s string) error {
+			if n, err := strconv.Atoi(s); err != nil || n < 0 {
+				return errors.New("请输入非负整数，0 表示不提示")
+			}
+			return nil
+		}
+		e.OnChanged = func(s string) {
+			if e.Validate() != nil {
+				return
+			}
+			n, _ := strconv.Atoi(s)
+			p.SetInt(key, n)
+			onChange()
+		}
+		return e
+	}
+
+	appearance := widget.NewForm(
+		widget.NewFormItem("主题色", container.NewVBox(accentSelect, accentEntry)),
+		widget.NewFormItem("深浅色", variantSelect),
+		widget.NewFormItem("输入框位置", inputPos),
+		widget.NewFormItem("撰写模式行数", composerEntry),
+		widget.NewFormItem("分栏", splitView),
+		widget.NewFormItem("列表顺序", newestFirst),
+		widget.NewFormItem("分隔线", separatorSelect),
+		widget.NewFormItem("附加信息", densitySelect),
+		widget.NewFormItem("序号", rowNumbers),
+		widget.NewFormItem("状态栏", statusBar),
+		widget.NewFormItem("条数超过时变黄", wipEntry(prefWIPWarn)),
+		widget.NewFormItem("条数超过时变红", wipEntry(prefWIPLimit)),
+		widget.NewFormItem("每条最多显示行数", linesEntry),
+		widget.NewFormItem("每次显示条数", rowLimitEntry),
+		widget.NewFormItem("高优先级", emphasisSelect),
+		widget.NewFormItem("文字方向", dirSelect),
+		widget.NewFormItem("链接", linkTitlesCheck),
+		widget.NewFormItem("托盘菜单", trayGroup),
+		widget.NewFormItem("托盘文字长度", trayLenEntry),
+		widget.NewFormItem("托盘延迟（秒）", trayDelayEntry),
+		widget.NewFormItem("窗口图标", container.NewBorder(nil, nil, nil, iconBrowse, iconEntry)),
+	)
+	exportStamp := widget.NewCheck("导出文件名带上时刻，避免覆盖已导出的文件", func(on bool) {
+		p.SetBool(prefExportStamp, on)
+	})
+	exportStamp.SetChecked(p.Bool(prefExportStamp))
+	control := widget.NewCheck("开启 "+controlSocket+"（重启后生效）", func(on bool) {
+		p.SetBool(prefControlSocket, on)
+	})
+	control.SetChecked(p.Bool(prefControlSocket))
+
+	metrics := widget.NewCheck("开启 HTTP 指标接口 /metrics（重启后生效）", func(on bool) {
+		p.SetBool(prefMetrics, on)
+	})
+	metrics.SetChecked(p.Bool(prefMetrics))
+	sharePage := widget.NewCheck("开启只读待办页面 /，可在浏览器中查看（重启后生效）", func(on bool) {
+		p.SetBool(prefSharePage, on)
+	})
+	sharePage.SetChecked(p.Bool(prefSharePage))
+	metricsAddr := widget.NewEntry()
+	metricsAddr.SetPlaceHolder(defaultMetricsAddr)
+	metricsAddr.SetText(p.String(prefMetricsAddr))
+	metricsAddr.Validator = func(s string) error {
+		if s == "" {
+			return nil
+		}
+		if _, _, err := net.SplitHostPort(s); err != nil {
+			return errors.New("格式为 主机:端口，例如 " + defaultMetricsAddr)
+		}
+		return nil
+	}
+	metricsAddr.OnChanged = func(s string) {
+		if metricsAddr.Validate() == nil {
+			p.SetString(prefMetricsAddr, s)
+		}
+	}
+
+	httpHelp := widget.NewLabel("默认只有本机可以访问；要让局域网内的其他设备查看，改为 0.0.0.0:端口 或本机的局域网地址")
+	httpHelp.Wrapping = fyne.TextWrapWord
+	httpHelp.Importance = widget.LowImportance
+
+	changeLog := widget.NewCheck("把每次修改追加到 "+changeLogFile, func(on bool) {
+		p.SetBool(prefChangeLog, on)
+	})
+	changeLog.SetChecked(p.Bool(prefChangeLog))
+
+	exportDir := widget.NewEntry()
+	exportDir.SetPlaceHolder("留空不自动导出")
+	exportDir.SetText(p.String(prefAutoExportDir))
+	exportDir.OnChanged = func(dir string) {
+		p.SetString(prefAutoExportDir, strings.TrimSpace(dir))
+	}
+	exportBrowse := widget.NewButton("选择…", func() {
+		dialog.ShowFolderOpen(func(u fyne.ListableURI, err error) {
+			if err != nil || u == nil {
+				return
+			}
+			exportDir.SetText(u.Path())
+		}, settingsWin)
+	})
+	exportFormats := []struct{ format, label string }{
+		{"json", "JSON（完整数据）"},
+		{"md", "Markdown 清单"},
+	}
+	var formatLabels []string
+	for _, f := range exportFormats {
+		formatLabels = append(formatLabels, f.label)
+	}
+	formatSelect := widget.NewSelect(formatLabels, nil)
+	formatSelect.SetSelectedIndex(0)
+	for i, f := range exportFormats {
+		if f.format == p.String(prefAutoExportFormat) {
+			formatSelect.SetSelectedIndex(i)
+		}
+	}
+	formatSelect.OnChanged = func(string) {
+		p.SetString(prefAutoExportFormat, exportFormats[formatSelect.SelectedIndex()].format)
+	}
+	cadences := []struct{ cadence, label string }{
+		{autoExportOff, "不导出"},
+		{autoExportDaily, "每天"},
+		{autoExportWeekly, "每周"},
+		{autoExportOnQuit, "每次退出时"},
+	}
+	var cadenceLabels []string
+	for _, c := range cadences {
+		cadenceLabels = append(cadenceLabels, c.label)
+	}
+	cadenceSelect := widget.NewSelect(cadenceLabels, nil)
+	for i, c := range cadences {
+		if c.cadence == p.String(prefAutoExportCadence) {
+			cadenceSelect.SetSelectedIndex(i)
+		}
+	}
+	cadenceSelect.OnChanged = func(string) {
+		p.SetString(prefAutoExportCadence, cadences[cadenceSelect.SelectedIndex()].cadence)
+	}
+	keepEntry := widget.NewEntry()
+	keepEntry.SetText(strconv.Itoa(p.IntWithFallback(prefAutoExportKeep, defaultAutoExportKeep)))
+	keepEntry.Validator = func(s string) error {
+		n, err := strconv.Atoi(s)
+		if err != nil || n < 1 || n > maxAutoExportKeep {
+			return fmt.Errorf("请输入 1-%d 之间的整数", maxAutoExportKeep)
+		}
+		return nil
+	}
+	keepEntry.OnChanged = func(s string) {
+		if keepEntry.Validate() != nil {
+			return
+		}
+		n, _ := strconv.Atoi(s)
+		p.SetInt(prefAutoExportKeep, n)
+	}
+
+	storage := widget.NewForm(
+		widget.NewFormItem("数据文件", pathLabel),
+		widget.NewFormItem("保存方式", saveSelect),
+		widget.NewFormItem("修改记录", changeLog),
+		widget.NewFormItem("控制接口", control),
+		widget.NewFormItem("HTTP 接口", container.NewVBox(metrics, sharePage, metricsAddr, httpHelp)),
+		widget.NewFormItem("导出", exportStamp),
+		widget.NewFormItem("自动导出到", container.NewBorder(nil, nil, nil, exportBrowse, exportDir)),
+		widget.NewFormItem("导出格式", formatSelect),
+		widget.NewFormItem("导出频率", cadenceSelect),
+		widget.NewFormItem("保留份数", keepEntry),
+	)
+
+	w := a.NewWindow("设置")
+	w.SetContent(container.NewVScroll(container.NewVBox(
+		widget.NewCard("外观", "", appearance),
+		widget.NewCard("行为", "", behavior),
+		widget.NewCard("提醒", "", reminders),
+		widget.NewCard("存储", "", storage),
+	)))
+	w.Resize(fyne.NewSize(360, 400))
+	w.SetOnClosed(func() {
+		settingsWin = nil
+	})
+	settingsWin = w
+	w.Show()
+}
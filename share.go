@@ -0,0 +1,18 @@
+package main
+
+import (
+	"fyne.io/fyne/v2"
+)
+
+// shareSheet 调用系统分享面板；Fyne 目前没有提供分享接口，桌面平台上为 nil，
+// 以后接入平台实现时在此赋值即可
+var shareSheet func(text string) error
+
+// shareText 优先使用系统分享，不支持或失败时退回复制到剪贴板并提示
+func shareText(a fyne.App, win fyne.Window, text string) {
+	if shareSheet != nil && shareSheet(text) == nil {
+		return
+	}
+	a.Clipboard().SetContent(text)
+	showTemporaryPopUp(win.Canvas(), "当前系统不支持分享，已复制到剪贴板", 2)
+}
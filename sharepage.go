@@ -0,0 +1,80 @@
+package main
+
+import (
+	"html/template"
+	"time"
+)
+
+// shareRefresh 只读页面自动刷新的间隔（秒）
+const shareRefresh = 30
+
+// shareItem 只读页面中的一行
+type shareItem struct {
+	Text, Meta    string
+	Done, Overdue bool
+}
+
+// sharePage 只读页面的数据
+type sharePage struct {
+	Refresh int
+	Updated string
+	Active  int
+	Items   []shareItem
+}
+
+// shareTemplate 适合手机浏览器的简单页面，按 shareRefresh 自动刷新
+var shareTemplate = template.Must(template.New("share").Parse(`<!DOCTYPE html>
+<html lang="zh-CN">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<meta http-equiv="refresh" content="{{.Refresh}}">
+<title>` + winTitle + `</title>
+<style>
+body { font-family: system-ui, sans-serif; margin: 0 auto; max-width: 40em; padding: 1em; color: #222; }
+h1 { font-size: 1.3em; margin: 0 0 .2em; }
+.updated { color: #888; font-size: .85em; margin-bottom: 1em; }
+ul { list-style: none; padding: 0; margin: 0; }
+li { padding: .6em 0; border-bottom: 1px solid #eee; word-wrap: break-word; }
+.meta { color: #666; font-size: .85em; margin-top: .2em; }
+.done { color: #999; text-decoration: line-through; }
+.overdue .meta { color: #c62828; }
+@media (prefers-color-scheme: dark) {
+  body { background: #121212; color: #ddd; }
+  li { border-color: #333; }
+}
+</style>
+</head>
+<body>
+<h1>` + winTitle + `（{{.Active}}）</h1>
+<div class="updated">更新于 {{.Updated}}，每 {{.Refresh}} 秒刷新</div>
+<ul>
+{{- range .Items}}
+<li{{if .Done}} class="done"{{else if .Overdue}} class="overdue"{{end}}>{{if .Done}}✔ {{end}}{{.Text}}{{if .Meta}}<div class="meta">{{.Meta}}</div>{{end}}</li>
+{{- else}}
+<li>没有待办</li>
+{{- end}}
+</ul>
+</body>
+</html>
+`))
+
+// buildSharePage 与主窗口的"全部"视图一致，只列出已到开始日期的待办
+func buildSharePage(todos []Todo, now time.Time) sharePage {
+	page := sharePage{Refresh: shareRefresh, Updated: now.Format("2006-01-02 15:04")}
+	for _, t := range todos {
+		if t.deferred(now) {
+			continue
+		}
+		if !t.Done {
+			page.Active++
+		}
+		page.Items = append(page.Items, shareItem{
+			Text:    t.Text,
+			Meta:    t.metaText(now),
+			Done:    t.Done,
+			Overdue: !t.Done && t.overdue(now),
+		})
+	}
+	return page
+}
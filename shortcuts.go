@@ -0,0 +1,73 @@
+package main
+
+import (
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/driver/desktop"
+	"fyne.io/fyne/v2/widget"
+)
+
+// keyAction 快捷键登记表中的一项：keys 与 help 用于帮助窗口，shortcuts 为注册到窗口的组合键，
+// 按下时调用 run。shortcuts 为空的按键由控件自行处理，只在帮助中列出；
+// command 不为空时同时以该名称列入命令面板
+type keyAction struct {
+	keys, help string
+	command    string
+	shortcuts  []fyne.Shortcut
+	run        func()
+}
+
+// ctrlKey Ctrl（macOS 上为 Cmd）加 key 的组合键
+func ctrlKey(key fyne.KeyName) fyne.Shortcut {
+	return &desktop.CustomShortcut{KeyName: key, Modifier: fyne.KeyModifierShortcutDefault}
+}
+
+// keyCommands 登记表中列入命令面板的操作，附带对应的快捷键说明
+func keyCommands(actions []keyAction) []command {
+	var cmds []command
+	for _, a := range actions {
+		if a.command != "" && a.run != nil {
+			cmds = append(cmds, command{name: a.command, keys: a.keys, run: a.run})
+		}
+	}
+	return cmds
+}
+
+// addShortcuts 把登记表中的组合键注册到 c 上
+func addShortcuts(c fyne.Canvas, actions []keyAction) {
+	for _, a := range actions {
+		for _, sc := range a.shortcuts {
+			c.AddShortcut(sc, func(fyne.Shortcut) { a.run() })
+		}
+	}
+}
+
+// shortcutHelp 当前显示的快捷键帮助，同一时间只显示一个
+var shortcutHelp dialog.Dialog
+
+// showShortcutHelp 按登记表的顺序显示快捷键列表，按 Esc 或"关闭"退出
+func showShortcutHelp(win fyne.Window, actions []keyAction) {
+	if shortcutHelp != nil {
+		return
+	}
+	grid := container.NewGridWithColumns(2)
+	for _, k := range actions {
+		keys := widget.NewLabel(k.keys)
+		keys.TextStyle = fyne.TextStyle{Monospace: true}
+		grid.Add(keys)
+		grid.Add(widget.NewLabel(k.help))
+	}
+	shortcutHelp = dialog.NewCustom("快捷键", "关闭", grid, win)
+	shortcutHelp.SetOnClosed(func() { shortcutHelp = nil })
+	shortcutHelp.Show()
+}
+
+// hideShortcutHelp 关闭正在显示的快捷键帮助，没有显示时返回 false
+func hideShortcutHelp() bool {
+	if shortcutHelp == nil {
+		return false
+	}
+	shortcutHelp.Hide()
+	return true
+}
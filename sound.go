@@ -0,0 +1,138 @@
+package main
+
+import (
+	"bytes"
+	"encoding/binary"
+	"log/slog"
+	"math"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"sync"
+
+	"fyne.io/fyne/v2"
+)
+
+// 提醒声音。Fyne 的通知无法控制系统提示音，"静音"只是不再额外播放提示音，
+// 通知本身是否出声由系统的通知设置决定
+const (
+	soundSystem = "system" // 只发送通知，声音由系统决定
+	soundChime  = "chime"  // 发送通知并播放内置提示音
+	soundSilent = "silent" // 不播放提示音
+)
+
+// soundOptions 设置与右键菜单中的选项，按显示顺序排列
+var soundOptions = []struct{ sound, label string }{
+	{soundSystem, "系统默认"},
+	{soundChime, "提示音"},
+	{soundSilent, "静音"},
+}
+
+// knownSound 判断是否为 soundOptions 中的取值
+func knownSound(sound string) bool {
+	for _, o := range soundOptions {
+		if o.sound == sound {
+			return true
+		}
+	}
+	return false
+}
+
+// soundLabel 提醒声音的显示名称，未知的值显示为系统默认
+func soundLabel(sound string) string {
+	for _, o := range soundOptions {
+		if o.sound == sound {
+			return o.label
+		}
+	}
+	return soundOptions[0].label
+}
+
+// reminderSound 待办的提醒声音，未单独设置时使用全局默认值
+func (t Todo) reminderSound(p fyne.Preferences) string {
+	if t.Sound != "" {
+		return t.Sound
+	}
+	return p.StringWithFallback(prefRemindSound, soundSystem)
+}
+
+var chimeOnce struct {
+	sync.Once
+	path string
+}
+
+// chimeFile 第一次播放时把内置提示音写入临时目录，失败时返回空路径
+func chimeFile() string {
+	chimeOnce.Do(func() {
+		path := filepath.Join(os.TempDir(), "mytodo-chime.wav")
+		if err := os.WriteFile(path, chimeWAV(), 0644); err != nil {
+			slog.Warn("write chime failed", "file", path, "err", err)
+			return
+		}
+		chimeOnce.path = path
+	})
+	return chimeOnce.path
+}
+
+// chimeWAV 生成两声渐弱的正弦波提示音，16 位单声道 WAV
+func chimeWAV() []byte {
+	const rate = 22050
+	var samples []int16
+	for _, tone := range []struct{ freq, secs float64 }{{880, 0.18}, {1320, 0.32}} {
+		n := int(tone.secs * rate)
+		for i := range n {
+			decay := 1 - float64(i)/float64(n)
+			v := math.Sin(2*math.Pi*tone.freq*float64(i)/rate) * decay * 0.4
+			samples = append(samples, int16(v*math.MaxInt16))
+		}
+	}
+	var b bytes.Buffer
+	size := uint32(len(samples) * 2)
+	b.WriteString("RIFF")
+	_ = binary.Write(&b, binary.LittleEndian, 36+size)
+	b.WriteString("WAVEfmt ")
+	// fmt 块：PCM、单声道、采样率、字节率、块对齐、位深
+	for _, v := range []any{uint32(16), uint16(1), uint16(1), uint32(rate), uint32(rate * 2), uint16(2), uint16(16)} {
+		_ = binary.Write(&b, binary.LittleEndian, v)
+	}
+	b.WriteString("data")
+	_ = binary.Write(&b, binary.LittleEndian, size)
+	_ = binary.Write(&b, binary.LittleEndian, samples)
+	return b.Bytes()
+}
+
+// chimeCommand 返回播放 WAV 文件的系统命令，没有可用的播放器时返回 nil
+func chimeCommand(path string) *exec.Cmd {
+	switch runtime.GOOS {
+	case "darwin":
+		return exec.Command("afplay", path)
+	case "windows":
+		return exec.Command("powershell", "-NoProfile", "-Command",
+			"(New-Object Media.SoundPlayer '"+path+"').PlaySync()")
+	}
+	for _, player := range []string{"paplay", "pw-play", "aplay"} {
+		if _, err := exec.LookPath(player); err == nil {
+			return exec.Command(player, path)
+		}
+	}
+	return nil
+}
+
+// playChime 在后台播放内置提示音，失败只记录日志
+func playChime() {
+	path := chimeFile()
+	if path == "" {
+		return
+	}
+	cmd := chimeCommand(path)
+	if cmd == nil {
+		slog.Warn("no audio player found for chime")
+		return
+	}
+	go func() {
+		if out, err := cmd.CombinedOutput(); err != nil {
+			slog.Warn("play chime failed", "cmd", cmd.Args[0], "err", err, "output", string(out))
+		}
+	}()
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+)
+
+// splitDelims 拆分待办时可选的分隔符，第一项为默认
+var splitDelims = []struct{ sep, label string }{
+	{"，", "中文逗号 ，"},
+	{",", "英文逗号 ,"},
+	{"、", "顿号 、"},
+	{"；", "分号 ；"},
+	{"和", "“和”"},
+	{"\n", "换行"},
+}
+
+// splitText 按分隔符拆分文字，去掉首尾空白并丢弃空的部分
+func splitText(text, sep string) []string {
+	var parts []string
+	for _, part := range strings.Split(text, sep) {
+		if part = strings.TrimSpace(part); part != "" {
+			parts = append(parts, part)
+		}
+	}
+	return parts
+}
+
+// showSplitDialog 选择分隔符并预览拆分结果，确认后以拆出的各部分调用 onSplit；
+// 只能拆出一条时不允许确认
+func showSplitDialog(win fyne.Window, text string, onSplit func(parts []string)) {
+	var labels []string
+	for _, d := range splitDelims {
+		labels = append(labels, d.label)
+	}
+	preview := widget.NewLabel("")
+	preview.Wrapping = fyne.TextWrapWord
+	var parts []string
+	delim := widget.NewSelect(labels, func(string) {})
+	delim.OnChanged = func(string) {
+		parts = splitText(text, splitDelims[delim.SelectedIndex()].sep)
+		if len(parts) < 2 {
+			preview.SetText("按此分隔符无法拆分")
+			return
+		}
+		lines := make([]string, len(parts))
+		for i, part := range parts {
+			lines[i] = "• " + part
+		}
+		preview.SetText(strings.Join(lines, "\n"))
+	}
+	// 默认选中第一个能拆出多条的分隔符
+	delim.SetSelectedIndex(0)
+	for i, sd := range splitDelims {
+		if len(splitText(text, sd.sep)) > 1 {
+			delim.SetSelectedIndex(i)
+			break
+		}
+	}
+	d := dialog.NewForm("拆分待办", "拆分", "取消", []*widget.FormItem{
+		widget.NewFormItem("分隔符", delim),
+		widget.NewFormItem("拆分为", preview),
+	}, func(ok bool) {
+		if ok && len(parts) > 1 {
+			onSplit(parts)
+		}
+	}, win)
+	d.Resize(fyne.NewSize(win.Canvas().Size().Width*0.8, 0))
+	d.Show()
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/layout"
+	"fyne.io/fyne/v2/widget"
+)
+
+// statusChips 状态栏中的快捷筛选，对应视图下拉框的选项
+var statusChips = []string{"全部", "未完成", "逾期"}
+
+// statusBar 列表下方的计数与快捷筛选
+type statusBar struct {
+	content *fyne.Container
+	done    *widget.Label
+	chips   []*widget.Button
+}
+
+// newStatusBar 点击筛选按钮时以对应视图调用 onPick
+func newStatusBar(onPick func(option string)) *statusBar {
+	b := &statusBar{done: widget.NewLabel("")}
+	b.done.Importance = widget.LowImportance
+	b.content = container.NewHBox(b.done, layout.NewSpacer())
+	for _, option := range statusChips {
+		chip := widget.NewButton(option, func() { onPick(option) })
+		chip.Importance = widget.LowImportance
+		b.chips = append(b.chips, chip)
+		b.content.Add(chip)
+	}
+	return b
+}
+
+// update 刷新已完成的条数与各筛选的条数，当前视图高亮；"未完成"的条数超过设置的阈值时
+// 改为黄色或红色，提醒先完成一些再添加
+func (b *statusBar) update(p fyne.Preferences, todos []Todo, selected string, now time.Time) {
+	done := 0
+	for _, t := range todos {
+		if t.Done {
+			done++
+		}
+	}
+	b.done.SetText(fmt.Sprintf("已完成 %d", done))
+	warn, limit := wipThresholds(p)
+	for i, option := range statusChips {
+		n := 0
+		for _, t := range todos {
+			if viewMatch(option, t, now) {
+				n++
+			}
+		}
+		chip := b.chips[i]
+		chip.SetText(fmt.Sprintf("%s %d", option, n))
+		chip.Importance = widget.LowImportance
+		if option == selected {
+			chip.Importance = widget.HighImportance
+		}
+		if option == "未完成" {
+			switch {
+			case limit > 0 && n > limit:
+				chip.Importance = widget.DangerImportance
+			case warn > 0 && n > warn:
+				chip.Importance = widget.WarningImportance
+			}
+		}
+		chip.Refresh()
+	}
+}
@@ -0,0 +1,350 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// 保存策略
+const (
+	saveImmediate  = "immediate"  // 每次修改立即写盘
+	saveDebounced  = "debounce"   // 停止修改 saveDelay 后写盘
+	saveOnQuit     = "quit"       // 只在退出时写盘
+	saveBackground = "background" // 在后台协程写盘，适合较慢的网络磁盘
+)
+
+const (
+	saveDelay = 2 * time.Second
+	slowSave  = 300 * time.Millisecond // 立即保存超过此时长时提示改用后台保存
+)
+
+var (
+	saveMu    sync.Mutex
+	saveMode  = saveImmediate
+	pending   []Todo // 尚未写盘的快照
+	saveTimer *time.Timer
+
+	// writeMu 保证同一时间只有一次写盘，后台写入与 flushSave 依次进行
+	writeMu    sync.Mutex
+	saveSignal chan struct{} // 通知后台协程有新的快照，第一次后台保存时创建
+	slowWarned bool
+
+	// onSaveState 后台保存开始与结束时在后台协程中调用，err 为写盘结果
+	onSaveState func(saving bool, err error)
+	// onSlowSave 立即保存明显较慢时调用一次
+	onSlowSave func(d time.Duration)
+	// onLossySave txt 格式第一次丢弃文字以外的内容时调用，可能在后台协程中
+	onLossySave func()
+)
+
+// setSaveMode 切换保存策略，切换前先写入尚未保存的内容
+func setSaveMode(mode string) {
+	flushSave()
+	saveMu.Lock()
+	saveMode = mode
+	saveMu.Unlock()
+}
+
+// flushSave 立即写入尚未保存的内容，退出或收到信号时调用；
+// 后台正在写盘时先等它完成
+func flushSave() {
+	writeMu.Lock()
+	defer writeMu.Unlock()
+	saveMu.Lock()
+	defer saveMu.Unlock()
+	if saveTimer != nil {
+		saveTimer.Stop()
+	}
+	if pending == nil {
+		return
+	}
+	if err := store.save(pending); err != nil {
+		slog.Error("save failed", "file", store.file(), "err", err)
+		return
+	}
+	slog.Debug("saved", "file", store.file(), "count", len(pending))
+	pending = nil
+}
+
+const (
+	dataFile = "todo.json"
+	textFile = "todo.txt"
+	gzipFile = dataFile + ".gz" // -format gzip 使用的压缩数据文件
+
+	schemaVersion = 1 // 当前数据格式版本
+)
+
+// todoStore 抽象待办事项的持久化方式
+type todoStore interface {
+	load() ([]Todo, error)
+	save(todos []Todo) error
+	file() string
+}
+
+// store 为当前使用的存储后端，由 -format 参数决定
+var store todoStore = jsonStore{path: dataFile}
+
+func useStore(format string) error {
+	switch format {
+	case "json":
+		// 只有压缩文件时沿用它，以免忘记加 -format gzip 时看起来像数据丢失
+		store = jsonStore{path: dataFile}
+		if _, err := os.Stat(dataFile); os.IsNotExist(err) {
+			if _, err := os.Stat(gzipFile); err == nil {
+				store = jsonStore{path: gzipFile}
+			}
+		}
+	case "gzip":
+		// 只有未压缩的文件时从它读取，第一次保存写入压缩文件后删除它，两份文件不会各自变化
+		store = jsonStore{path: gzipFile}
+		if _, err := os.Stat(gzipFile); os.IsNotExist(err) {
+			if _, err := os.Stat(dataFile); err == nil {
+				store = jsonStore{path: gzipFile, migrate: dataFile}
+			}
+		}
+	case "txt":
+		store = &textStore{path: textFile}
+	default:
+		return fmt.Errorf("unknown storage format %q", format)
+	}
+	return nil
+}
+
+// storePath 返回当前存储后端使用的数据文件
+func storePath() string {
+	return store.file()
+}
+
+func loadTodos() ([]Todo, error) {
+	todos, err := store.load()
+	if todos != nil {
+		mtime := time.Now()
+		if fi, serr := os.Stat(store.file()); serr == nil {
+			mtime = fi.ModTime()
+		}
+		fillTimestamps(todos, mtime)
+		fillIDs(todos)
+	}
+	slog.Debug("loaded", "file", store.file(), "count", len(todos), "err", err)
+	return todos, err
+}
+
+// saveTodos 按当前保存策略写入，非立即保存时先保存一份深拷贝的快照等待写盘，
+// 写盘可能在其它协程中进行，不能与当前列表共用指针和标签切片
+func saveTodos(todos []Todo) {
+	saveMu.Lock()
+	defer saveMu.Unlock()
+	switch saveMode {
+	case saveDebounced:
+		pending = cloneTodos(todos)
+		if saveTimer == nil {
+			saveTimer = time.AfterFunc(saveDelay, func() { flushSave() })
+		} else {
+			saveTimer.Reset(saveDelay)
+		}
+	case saveOnQuit:
+		pending = cloneTodos(todos)
+	case saveBackground:
+		pending = cloneTodos(todos)
+		if saveSignal == nil {
+			saveSignal = make(chan struct{}, 1)
+			go backgroundSaver()
+		}
+		select {
+		case saveSignal <- struct{}{}:
+		default: // 已有待处理的通知，写盘时会取到最新的快照
+		}
+	default:
+		pending = nil
+		start := time.Now()
+		if err := store.save(todos); err != nil {
+			slog.Error("save failed", "file", store.file(), "err", err)
+			return
+		}
+		d := time.Since(start)
+		slog.Debug("saved", "file", store.file(), "count", len(todos), "took", d)
+		if d > slowSave && !slowWarned && onSlowSave != nil {
+			slowWarned = true
+			onSlowSave(d)
+		}
+	}
+}
+
+// backgroundSaver 后台保存的写盘协程。每次只写当时最新的快照，
+// 因此较早的快照不会在较新的之后写入
+func backgroundSaver() {
+	for range saveSignal {
+		writeMu.Lock()
+		saveMu.Lock()
+		snap := pending
+		pending = nil
+		saveMu.Unlock()
+		if snap != nil {
+			if onSaveState != nil {
+				onSaveState(true, nil)
+			}
+			err := store.save(snap)
+			if err != nil {
+				slog.Error("save failed", "file", store.file(), "err", err)
+			} else {
+				slog.Debug("saved", "file", store.file(), "count", len(snap))
+			}
+			if onSaveState != nil {
+				onSaveState(false, err)
+			}
+		}
+		writeMu.Unlock()
+	}
+}
+
+// jsonStore 以 JSON 数组保存全部字段，文件名以 .gz 结尾时压缩保存。
+// migrate 不为空时从该文件读取，保存到 path 后删除它
+type jsonStore struct {
+	path    string
+	migrate string
+}
+
+// gzipMagic gzip 文件头，读取时据此判断是否需要解压，而不依赖扩展名
+var gzipMagic = []byte{0x1f, 0x8b}
+
+// decodeData 解压 gzip 格式的内容，未压缩的原样返回
+func decodeData(data []byte) ([]byte, error) {
+	if !bytes.HasPrefix(data, gzipMagic) {
+		return data, nil
+	}
+	r, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("解压失败：%w", err)
+	}
+	defer r.Close()
+	out, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("解压失败：%w", err)
+	}
+	return out, nil
+}
+
+// encodeData 按文件扩展名决定是否压缩写入的内容
+func encodeData(path string, data []byte) []byte {
+	if !strings.HasSuffix(path, ".gz") {
+		return data
+	}
+	var buf bytes.Buffer
+	w := gzip.NewWriter(&buf)
+	_, _ = w.Write(data)
+	_ = w.Close()
+	return buf.Bytes()
+}
+
+func (s jsonStore) file() string { return s.path }
+
+func (s jsonStore) load() ([]Todo, error) {
+	path := s.path
+	if s.migrate != "" {
+		path = s.migrate
+	}
+	data, err := readDataFile(path)
+	if os.IsNotExist(err) {
+		return []Todo{}, nil
+	}
+	if err != nil && err != errChecksum {
+		return nil, err
+	}
+	data, derr := decodeData(data)
+	var todos []Todo
+	if derr == nil {
+		derr = json.Unmarshal(data, &todos)
+	}
+	if derr != nil {
+		// 有备份时交给调用方提示恢复，而不是直接退出；迁移中的文件不在 file() 上，不提示
+		if s.migrate == "" && hasBackup(path) {
+			return []Todo{}, fmt.Errorf("%w：%v", errUnreadable, derr)
+		}
+		return nil, derr
+	}
+	return todos, err
+}
+
+func (s jsonStore) save(todos []Todo) error {
+	// 校验文件与备份针对磁盘上的内容，压缩后照常生成
+	if err := writeDataFile(s.path, encodeData(s.path, encodeTodos(todos))); err != nil {
+		return err
+	}
+	if s.migrate != "" {
+		for _, f := range []string{s.migrate, checksumPath(s.migrate)} {
+			if err := os.Remove(f); err != nil && !os.IsNotExist(err) {
+				slog.Warn("remove migrated file failed", "file", f, "err", err)
+			}
+		}
+	}
+	return nil
+}
+
+// encodeTodos 数据文件与 JSON 导出共用的序列化方式
+func encodeTodos(todos []Todo) []byte {
+	data, _ := json.MarshalIndent(todos, "", "  ")
+	return data
+}
+
+// textStore 以纯文本保存，每行一条，只保留文字内容
+type textStore struct {
+	path   string
+	warned bool
+}
+
+func (s *textStore) file() string { return s.path }
+
+func (s *textStore) load() ([]Todo, error) {
+	data, err := readDataFile(s.path)
+	if os.IsNotExist(err) {
+		return []Todo{}, nil
+	}
+	if err != nil && err != errChecksum {
+		return nil, err
+	}
+	todos, perr := s.parse(data)
+	if perr != nil {
+		return nil, perr
+	}
+	return todos, err
+}
+
+// parse 按行解析，跳过空行
+func (s *textStore) parse(data []byte) ([]Todo, error) {
+	todos := []Todo{}
+	sc := bufio.NewScanner(bytes.NewReader(data))
+	for sc.Scan() {
+		line := strings.TrimRight(sc.Text(), "\r")
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		todos = append(todos, Todo{Text: line})
+	}
+	return todos, sc.Err()
+}
+
+func (s *textStore) save(todos []Todo) error {
+	var buf bytes.Buffer
+	for _, t := range todos {
+		if t.hasMetadata() && !s.warned {
+			slog.Warn("txt 格式只保存文字内容，其它字段将被丢弃")
+			s.warned = true
+			if onLossySave != nil {
+				onLossySave()
+			}
+		}
+		// 换行会破坏每行一条的格式，替换为空格
+		buf.WriteString(strings.NewReplacer("\r\n", " ", "\n", " ", "\r", " ").Replace(t.Text))
+		buf.WriteByte('\n')
+	}
+	return writeDataFile(s.path, buf.Bytes())
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestJSONStoreMigrateToGzip(t *testing.T) {
+	dir := t.TempDir()
+	plain, gz := filepath.Join(dir, dataFile), filepath.Join(dir, gzipFile)
+	if err := writeDataFile(plain, encodeTodos([]Todo{{ID: "a", Text: "买牛奶"}, {ID: "b", Text: "写周报"}})); err != nil {
+		t.Fatal(err)
+	}
+
+	s := jsonStore{path: gz, migrate: plain}
+	todos, err := s.load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(todos) != 2 || todos[0].Text != "买牛奶" {
+		t.Fatalf("load from migrate source: got %+v", todos)
+	}
+	if err := s.save(todos); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, f := range []string{plain, checksumPath(plain)} {
+		if _, err := os.Stat(f); !os.IsNotExist(err) {
+			t.Errorf("%s still exists after migration (err %v)", filepath.Base(f), err)
+		}
+	}
+	data, err := os.ReadFile(gz)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.HasPrefix(data, gzipMagic) {
+		t.Errorf("%s is not gzip compressed", gzipFile)
+	}
+	got, err := jsonStore{path: gz}.load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 2 || got[1].Text != "写周报" {
+		t.Errorf("reload from gzip: got %+v", got)
+	}
+}
+
+func TestJSONStoreLoadMissing(t *testing.T) {
+	dir := t.TempDir()
+	for _, s := range []jsonStore{
+		{path: filepath.Join(dir, dataFile)},
+		{path: filepath.Join(dir, gzipFile), migrate: filepath.Join(dir, dataFile)},
+	} {
+		todos, err := s.load()
+		if err != nil || todos == nil || len(todos) != 0 {
+			t.Errorf("%+v: got %v, %v; want an empty list", s, todos, err)
+		}
+	}
+}
@@ -0,0 +1,154 @@
+package main
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/widget"
+)
+
+// tagCompleteMax 补全列表最多列出的标签数
+const tagCompleteMax = 5
+
+// tagsByFrequency 列表中出现过的标签，使用次数多的在前，次数相同按名称排序
+func tagsByFrequency(todos []Todo) []string {
+	count := map[string]int{}
+	for _, t := range todos {
+		for _, tag := range t.Tags {
+			count[tag]++
+		}
+	}
+	tags := make([]string, 0, len(count))
+	for tag := range count {
+		tags = append(tags, tag)
+	}
+	slices.SortFunc(tags, func(x, y string) int {
+		return cmp.Or(count[y]-count[x], strings.Compare(x, y))
+	})
+	return tags
+}
+
+// tagPrefix 返回光标前正在输入的 #标签（不含 #），光标前不是标签时 ok 为 false
+func tagPrefix(e *widget.Entry) (prefix string, ok bool) {
+	lines := strings.Split(e.Text, "\n")
+	if e.CursorRow >= len(lines) {
+		return "", false
+	}
+	line := []rune(lines[e.CursorRow])
+	before := string(line[:min(e.CursorColumn, len(line))])
+	i := strings.LastIndexAny(before, " \t")
+	word := before[i+1:]
+	if !strings.HasPrefix(word, "#") {
+		return "", false
+	}
+	return word[1:], true
+}
+
+// tagCompleter 主输入框的标签补全：输入 # 后在输入框旁列出已有标签，
+// 焦点始终留在输入框，上下键选择、回车插入、Esc 关闭。
+// 不使用弹出层，因为弹出层会接管键盘输入
+type tagCompleter struct {
+	content *fyne.Container
+	entry   *softEntry
+	tags    func() []string
+	matches []string
+	sel     int
+}
+
+func newTagCompleter(entry *softEntry, tags func() []string) *tagCompleter {
+	c := &tagCompleter{entry: entry, tags: tags, content: container.NewVBox()}
+	c.content.Hide()
+	return c
+}
+
+// update 输入内容变化后刷新补全列表，没有可补全的标签时关闭
+func (c *tagCompleter) update() {
+	prefix, ok := tagPrefix(&c.entry.Entry)
+	c.matches = c.matches[:0]
+	if ok {
+		for _, tag := range c.tags() {
+			if strings.HasPrefix(strings.ToLower(tag), strings.ToLower(prefix)) && tag != prefix {
+				c.matches = append(c.matches, tag)
+			}
+			if len(c.matches) == tagCompleteMax {
+				break
+			}
+		}
+	}
+	c.sel = 0
+	c.render()
+}
+
+// render 按当前的匹配结果重建列表，选中的一项以高亮显示
+func (c *tagCompleter) render() {
+	c.content.Objects = nil
+	for i, tag := range c.matches {
+		btn := widget.NewButton("#"+tag, func() { c.insert(tag) })
+		btn.Alignment = widget.ButtonAlignLeading
+		btn.Importance = widget.LowImportance
+		if i == c.sel {
+			btn.Importance = widget.HighImportance
+		}
+		c.content.Add(btn)
+	}
+	if len(c.matches) == 0 {
+		c.content.Hide()
+	} else {
+		c.content.Show()
+	}
+	c.content.Refresh()
+}
+
+// hide 关闭补全列表
+func (c *tagCompleter) hide() {
+	c.matches = nil
+	c.render()
+}
+
+// insert 用选中的标签替换光标前未输入完的部分，并在其后补一个空格
+func (c *tagCompleter) insert(tag string) {
+	prefix, ok := tagPrefix(&c.entry.Entry)
+	if !ok {
+		c.hide()
+		return
+	}
+	lines := strings.Split(c.entry.Text, "\n")
+	row := c.entry.CursorRow
+	line := []rune(lines[row])
+	col := min(c.entry.CursorColumn, len(line))
+	start := col - len([]rune(prefix))
+	lines[row] = string(line[:start]) + tag + " " + string(line[col:])
+	c.entry.SetText(strings.Join(lines, "\n"))
+	c.entry.CursorRow, c.entry.CursorColumn = row, start+len([]rune(tag))+1
+	c.entry.Refresh()
+	c.hide()
+	if cv := fyne.CurrentApp().Driver().CanvasForObject(c.entry); cv != nil {
+		cv.Focus(c.entry)
+	}
+}
+
+// typedKey 补全列表显示时处理上下键、回车与 Esc，已处理时返回 true
+func (c *tagCompleter) typedKey(key *fyne.KeyEvent) bool {
+	if len(c.matches) == 0 {
+		return false
+	}
+	switch key.Name {
+	case fyne.KeyUp, fyne.KeyDown:
+		delta := 1
+		if key.Name == fyne.KeyUp {
+			delta = -1
+		}
+		c.sel = (c.sel + delta + len(c.matches)) % len(c.matches)
+		c.render()
+	case fyne.KeyReturn, fyne.KeyEnter:
+		c.insert(c.matches[c.sel])
+	case fyne.KeyEscape:
+		c.hide()
+	default:
+		return false
+	}
+	return true
+}
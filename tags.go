@@ -0,0 +1,216 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"os"
+	"slices"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+)
+
+// tagsFile 标签登记表，记录每个标签的颜色，与数据文件放在一起
+const tagsFile = "tags.json"
+
+// tagColors 标签名到十六进制颜色的映射，没有登记的标签没有颜色
+var tagColors = map[string]string{}
+
+// loadTagColors 启动时读取标签颜色，文件不存在时为空；与数据文件一样校验，
+// 不符时记录警告并照常使用，损坏时可用 tags.json.bak 手动恢复
+func loadTagColors() {
+	data, err := readDataFile(tagsFile)
+	if os.IsNotExist(err) {
+		return
+	}
+	if err != nil {
+		slog.Warn("load tags failed", "file", tagsFile, "err", err)
+		if err != errChecksum {
+			return
+		}
+	}
+	if err := json.Unmarshal(data, &tagColors); err != nil {
+		slog.Warn("parse tags failed", "file", tagsFile, "err", err)
+		tagColors = map[string]string{}
+	}
+}
+
+// saveTagColors 写入标签颜色，与数据文件一样生成校验文件与备份，失败时只记录日志
+func saveTagColors() {
+	data, _ := json.MarshalIndent(tagColors, "", "  ")
+	if err := writeDataFile(tagsFile, data); err != nil {
+		slog.Error("save tags failed", "file", tagsFile, "err", err)
+	}
+}
+
+// rowColor 行首颜色条的颜色：单独设置的颜色优先，否则取第一个标签的颜色
+func rowColor(t Todo) string {
+	if t.Color != "" || len(t.Tags) == 0 {
+		return t.Color
+	}
+	return tagColors[t.Tags[0]]
+}
+
+// usedTags 列表中出现过的标签，按名称排序
+func usedTags(todos []Todo) []string {
+	var used []string
+	for _, t := range todos {
+		for _, tag := range t.Tags {
+			if !slices.Contains(used, tag) {
+				used = append(used, tag)
+			}
+		}
+	}
+	slices.Sort(used)
+	return used
+}
+
+// renameTags 按 renames 重命名标签，新名称为空表示移除该标签；
+// 改名后与已有标签重名时合并为一个
+func renameTags(tags []string, renames map[string]string) []string {
+	var out []string
+	for _, tag := range tags {
+		if to, ok := renames[tag]; ok {
+			tag = to
+		}
+		if tag != "" {
+			out = appendTag(out, tag)
+		}
+	}
+	return out
+}
+
+// normalizeTag 标签的规范形式：去掉首尾空白并转为小写，Work、work、WORK 视为同一个
+func normalizeTag(tag string) string {
+	return strings.ToLower(strings.TrimSpace(tag))
+}
+
+// normalizeTags 规范化每个标签并合并重复的，保持原有顺序
+func normalizeTags(tags []string) []string {
+	var out []string
+	for _, tag := range tags {
+		if tag = normalizeTag(tag); tag != "" {
+			out = appendTag(out, tag)
+		}
+	}
+	return out
+}
+
+// tagNormalization 列出不是规范形式的标签，映射到规范形式，供 renameTags 使用
+func tagNormalization(todos []Todo) map[string]string {
+	renames := map[string]string{}
+	for _, tag := range usedTags(todos) {
+		if to := normalizeTag(tag); to != tag {
+			renames[tag] = to
+		}
+	}
+	return renames
+}
+
+// renameTagColors 标签改名后把颜色移到新名称上，新名称已有颜色时保留新名称的
+func renameTagColors(renames map[string]string) {
+	changed := false
+	for from, to := range renames {
+		c, ok := tagColors[from]
+		if !ok {
+			continue
+		}
+		if _, exists := tagColors[to]; !exists && to != "" {
+			tagColors[to] = c
+		}
+		delete(tagColors, from)
+		changed = true
+	}
+	if changed {
+		saveTagColors()
+	}
+}
+
+// showTagManager 管理正在使用的标签：修改名称与颜色。保存时颜色立即写入登记表，
+// 然后以旧名到新名的映射回调 onSave，没有改名时映射为空
+func showTagManager(win fyne.Window, todos []Todo, onSave func(renames map[string]string)) {
+	tags := usedTags(todos)
+	if len(tags) == 0 {
+		dialog.ShowInformation("管理标签", "还没有待办使用标签，可在输入时用 #标签 添加", win)
+		return
+	}
+	colors := map[string]string{}
+	for _, tag := range tags {
+		colors[tag] = tagColors[tag]
+	}
+	names := map[string]*widget.Entry{}
+	rows := container.NewVBox()
+	for _, tag := range tags {
+		name := widget.NewEntry()
+		name.SetText(tag)
+		name.Validator = func(s string) error {
+			if strings.ContainsAny(strings.TrimPrefix(s, "#"), " \t\r\n") {
+				return errors.New("标签名不能包含空格")
+			}
+			return nil
+		}
+		names[tag] = name
+
+		swatch := canvas.NewRectangle(nil)
+		swatch.CornerRadius = 4
+		swatch.SetMinSize(fyne.NewSize(16, 16))
+		paint := func() {
+			c, ok := parseHexColor(colors[tag])
+			if !ok {
+				c = nil
+			}
+			swatch.FillColor = c
+			swatch.Refresh()
+		}
+		paint()
+		colorBtn := widget.NewButton("", func() {
+			showColorPicker(win, func(hex string) {
+				colors[tag] = hex
+				paint()
+			})
+		})
+		rows.Add(container.NewBorder(nil, nil, nil,
+			container.NewStack(colorBtn, container.NewPadded(swatch)), name))
+	}
+	help := widget.NewLabel("清空名称会从所有待办中移除该标签")
+	help.Importance = widget.LowImportance
+
+	d := dialog.NewCustomConfirm("管理标签", "保存", "取消",
+		container.NewBorder(nil, help, nil, nil, container.NewVScroll(rows)), func(ok bool) {
+			if !ok {
+				return
+			}
+			renames := map[string]string{}
+			for _, tag := range tags {
+				if names[tag].Validate() != nil {
+					continue
+				}
+				to := strings.TrimPrefix(strings.TrimSpace(names[tag].Text), "#")
+				if to != tag {
+					renames[tag] = to
+				}
+			}
+			// 颜色跟随标签名，改名时一并迁移；多个标签合并为一个时取第一个有颜色的
+			for _, tag := range tags {
+				delete(tagColors, tag)
+			}
+			for _, tag := range tags {
+				to, hex := tag, colors[tag]
+				if r, ok := renames[tag]; ok {
+					to = r
+				}
+				if to != "" && hex != "" && tagColors[to] == "" {
+					tagColors[to] = hex
+				}
+			}
+			saveTagColors()
+			onSave(renames)
+		}, win)
+	d.Resize(fyne.NewSize(320, 360))
+	d.Show()
+}
@@ -0,0 +1,170 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"fyne.io/fyne/v2"
+)
+
+var (
+	// errEmptyTodo 输入为空或只有空白，各添加途径都静默忽略
+	errEmptyTodo = errors.New("待办事项不能为空")
+	// errOnlyTokens 开启智能识别时输入只有 !优先级 @日期 #标签 等标记
+	errOnlyTokens = errors.New("只有标记，缺少待办内容")
+)
+
+// checkTodoText 按偏好设置整理输入内容并校验，返回整理后的文字。
+// 添加、编辑、导入与拆分共用，首尾空白总是去掉，开启合并空白时再合并中间的
+func checkTodoText(p fyne.Preferences, text string) (string, error) {
+	text = strings.TrimSpace(text)
+	if p.BoolWithFallback(prefNormalizeSpace, true) {
+		text = normalizeSpace(text)
+	}
+	if text == "" {
+		return "", errEmptyTodo
+	}
+	if limit := maxTextLen(p); utf8.RuneCountInString(text) > limit {
+		return "", fmt.Errorf("待办事项最多%d个汉字", limit)
+	}
+	return text, nil
+}
+
+// captureHelp 添加模板的占位符说明，设置与 README 共用
+const captureHelp = "可用 {text} {date} {time} {weekday}，不含 {text} 时作为前缀"
+
+// "复制"按钮与 Ctrl+C 复制的内容
+const (
+	copyFull      = ""      // 全文
+	copyFirstLine = "first" // 只复制第一行
+	copyClean     = "clean" // 去掉 !优先级 @日期 #标签 等行内标记
+)
+
+var copyModes = []struct{ mode, label string }{
+	{copyFull, "全文"},
+	{copyFirstLine, "第一行"},
+	{copyClean, "去掉标记"},
+}
+
+// copyVariant 按复制方式取出要复制的文字，返回文字与提示中显示的名称（全文时为空）
+func copyVariant(mode, text string, now time.Time) (string, string) {
+	switch mode {
+	case copyFirstLine:
+		first, _, _ := strings.Cut(text, "\n")
+		return strings.TrimRight(first, "\r"), "第一行"
+	case copyClean:
+		return parseTokens(text, now).Text, "去掉标记"
+	}
+	return text, ""
+}
+
+// weekdayNames {weekday} 占位符使用的星期名称，下标为 time.Weekday
+var weekdayNames = []string{"周日", "周一", "周二", "周三", "周四", "周五", "周六"}
+
+// applyCaptureTemplate 把输入框中的文字套入模板，空模板或空输入原样返回；
+// 结果仍由 checkTodoText 校验字数
+func applyCaptureTemplate(template, text string, now time.Time) string {
+	template = strings.TrimSpace(template)
+	if template == "" || strings.TrimSpace(text) == "" {
+		return text
+	}
+	if !strings.Contains(template, "{text}") {
+		template += " {text}"
+	}
+	return strings.NewReplacer(
+		"{date}", now.Format("2006-01-02"),
+		"{time}", now.Format("15:04"),
+		"{weekday}", weekdayNames[now.Weekday()],
+		"{text}", text,
+	).Replace(template)
+}
+
+// normalizeSpace 去掉首尾空白，并把连续的空格/制表符合并为一个空格
+func normalizeSpace(s string) string {
+	var b strings.Builder
+	space := false
+	for _, r := range strings.TrimSpace(s) {
+		if r == ' ' || r == '\t' {
+			space = true
+			continue
+		}
+		if space {
+			b.WriteByte(' ')
+			space = false
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// clampText 按逐字换行估算，截断到最多 maxLines 行并以省略号结尾；width 为可用宽度
+func clampText(text string, maxLines int, width float32, measure func(string) float32) string {
+	if maxLines <= 0 || width <= 0 {
+		return text
+	}
+	const ellipsis = "…"
+	runes := []rune(text)
+	line, lineWidth := 1, float32(0)
+	for i, r := range runes {
+		w := measure(string(r))
+		if lineWidth+w > width && lineWidth > 0 {
+			if line == maxLines {
+				// 从最后一行末尾去掉若干字，留出省略号的位置
+				room := width - lineWidth - measure(ellipsis)
+				for i > 0 && room < 0 {
+					i--
+					room += measure(string(runes[i]))
+				}
+				return string(runes[:i]) + ellipsis
+			}
+			line++
+			lineWidth = 0
+		}
+		lineWidth += w
+	}
+	return text
+}
+
+// 文字方向偏好
+const (
+	dirAuto = ""    // 按每条文字的第一个强方向字符判断
+	dirLTR  = "ltr" // 始终从左到右
+	dirRTL  = "rtl" // 始终从右到左
+)
+
+// isRTL 判断文字的第一个强方向字符是否属于从右到左书写的文字
+func isRTL(text string) bool {
+	for _, r := range text {
+		switch {
+		case unicode.In(r, unicode.Hebrew, unicode.Arabic, unicode.Syriac, unicode.Thaana, unicode.Nko):
+			return true
+		case unicode.IsLetter(r):
+			return false
+		}
+	}
+	return false
+}
+
+// rowRTL 按偏好决定这一行是否镜像排列
+func rowRTL(p fyne.Preferences, text string) bool {
+	switch p.String(prefTextDir) {
+	case dirLTR:
+		return false
+	case dirRTL:
+		return true
+	}
+	return isRTL(text)
+}
+
+// truncateRunes 按字符（而非字节）截断到最多 n 个字符，超出时以省略号结尾，避免截断多字节字符
+func truncateRunes(s string, n int) string {
+	if n <= 0 || utf8.RuneCountInString(s) <= n {
+		return s
+	}
+	r := []rune(s)
+	return string(r[:n-1]) + "…"
+}
@@ -0,0 +1,146 @@
+package main
+
+import (
+	"image/color"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/theme"
+)
+
+// accentCustom 主题色选择"自定义"时保存的值，颜色取 prefAccentColor
+const accentCustom = "custom"
+
+// accentPresets 内置的主题色，第一个为 Fyne 默认配色
+var accentPresets = []struct {
+	Name string
+	Hex  string
+}{
+	{"默认", ""},
+	{"蓝色", "#1e88e5"},
+	{"绿色", "#43a047"},
+	{"橙色", "#fb8c00"},
+	{"紫色", "#8e24aa"},
+	{"红色", "#e53935"},
+}
+
+// accentHex 返回偏好设置中的主题色，默认配色或颜色非法时返回空
+func accentHex(p fyne.Preferences) string {
+	name := p.String(prefAccent)
+	if name == accentCustom {
+		if _, ok := parseHexColor(p.String(prefAccentColor)); ok {
+			return p.String(prefAccentColor)
+		}
+		return ""
+	}
+	for _, a := range accentPresets {
+		if a.Name == name {
+			return a.Hex
+		}
+	}
+	return ""
+}
+
+// 界面缩放的范围与每次调整的步长，单位为百分比
+const (
+	defaultZoom = 100
+	minZoom     = 50
+	maxZoom     = 200
+	zoomStep    = 10
+)
+
+// zoomPercent 返回偏好设置中的缩放比例，超出范围时收拢到最近的有效值
+func zoomPercent(p fyne.Preferences) int {
+	return min(max(p.IntWithFallback(prefZoom, defaultZoom), minZoom), maxZoom)
+}
+
+// 深浅色模式，空为跟随系统
+const (
+	themeSystem = ""
+	themeLight  = "light"
+	themeDark   = "dark"
+)
+
+// themeVariants 按切换顺序列出深浅色模式
+var themeVariants = []struct{ variant, label string }{
+	{themeSystem, "跟随系统"},
+	{themeLight, "浅色"},
+	{themeDark, "深色"},
+}
+
+// nextThemeVariant 返回切换顺序中 current 的下一个模式
+func nextThemeVariant(current string) string {
+	for i, v := range themeVariants {
+		if v.variant == current {
+			return themeVariants[(i+1)%len(themeVariants)].variant
+		}
+	}
+	return themeSystem
+}
+
+// accentTheme 在默认主题上替换主色并按比例缩放尺寸，深浅色按偏好设置固定或跟随系统
+type accentTheme struct {
+	fyne.Theme
+	primary *color.NRGBA // 为空时使用默认配色
+	scale   float32
+	variant string // themeVariants 中的模式
+}
+
+// applyTheme 按偏好设置应用主题色与界面缩放
+func applyTheme(a fyne.App) {
+	t := &accentTheme{
+		Theme:   theme.DefaultTheme(),
+		scale:   float32(zoomPercent(a.Preferences())) / 100,
+		variant: a.Preferences().String(prefThemeVariant),
+	}
+	if c, ok := parseHexColor(accentHex(a.Preferences())); ok {
+		primary := c.(color.NRGBA)
+		t.primary = &primary
+	}
+	if t.primary == nil && t.scale == 1 && t.variant == themeSystem {
+		a.Settings().SetTheme(theme.DefaultTheme())
+		return
+	}
+	a.Settings().SetTheme(t)
+}
+
+// Size 所有尺寸（字号、内边距、图标等）按同一比例缩放
+func (t *accentTheme) Size(name fyne.ThemeSizeName) float32 {
+	return t.Theme.Size(name) * t.scale
+}
+
+func (t *accentTheme) Color(name fyne.ThemeColorName, variant fyne.ThemeVariant) color.Color {
+	switch t.variant {
+	case themeLight:
+		variant = theme.VariantLight
+	case themeDark:
+		variant = theme.VariantDark
+	}
+	if t.primary == nil {
+		return t.Theme.Color(name, variant)
+	}
+	switch name {
+	case theme.ColorNamePrimary, theme.ColorNameHyperlink:
+		return *t.primary
+	case theme.ColorNameFocus:
+		return withAlpha(*t.primary, 0x7f)
+	case theme.ColorNameSelection:
+		return withAlpha(*t.primary, 0x3f)
+	case theme.ColorNameForegroundOnPrimary:
+		// 浅色的主色上用黑字，深色的用白字，保证高亮按钮上的文字清晰
+		if luminance(*t.primary) > 0.5 {
+			return color.Black
+		}
+		return color.White
+	}
+	return t.Theme.Color(name, variant)
+}
+
+func withAlpha(c color.NRGBA, a uint8) color.NRGBA {
+	c.A = a
+	return c
+}
+
+// luminance 估算颜色的相对亮度，范围 0-1
+func luminance(c color.NRGBA) float64 {
+	return (0.299*float64(c.R) + 0.587*float64(c.G) + 0.114*float64(c.B)) / 255
+}
@@ -0,0 +1,387 @@
+package main
+
+import (
+	"crypto/rand"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+)
+
+type Todo struct {
+	ID           string     `json:"id,omitempty"` // 创建时生成的唯一标识，旧数据在加载时补全
+	Text         string     `json:"text"`
+	Color        string     `json:"color,omitempty"`      // 颜色标签，十六进制如 #e53935
+	Attachment   string     `json:"attachment,omitempty"` // 关联的本地文件路径
+	Priority     Priority   `json:"priority,omitempty"`
+	Due          *time.Time `json:"due,omitempty"`     // 截止时间，以 UTC 保存
+	StartAt      *time.Time `json:"startAt,omitempty"` // 开始日期，之前只在"未来"视图中显示
+	Tags         []string   `json:"tags,omitempty"`
+	Context      string     `json:"context,omitempty"`      // GTD 情境，如 @家、@电话，与标签分开
+	RemindBefore *int       `json:"remindBefore,omitempty"` // 提前提醒的分钟数，空为使用默认设置
+	Sound        string     `json:"sound,omitempty"`        // 提醒声音，见 soundOptions，空为使用默认设置
+	HideInTray   bool       `json:"hideInTray,omitempty"`   // 不在托盘菜单的待办列表中显示
+	Status       string     `json:"status,omitempty"`       // 空为进行中，StatusWaiting 为等待中
+	DependsOn    string     `json:"dependsOn,omitempty"`    // 依赖的待办 ID，依赖完成（已移除）前视为受阻
+	Source       string     `json:"source,omitempty"`       // 创建途径，见 sourceLabels，旧数据为空
+	Done         bool       `json:"done,omitempty"`         // 开启"完成后保留"时完成的待办留在列表中
+	CompletedAt  *time.Time `json:"completedAt,omitempty"`  // 完成时间，取消完成时清除
+	Outcome      string     `json:"outcome,omitempty"`      // 完成时记录的结果备注
+	CreatedAt    time.Time  `json:"createdAt"`
+	UpdatedAt    time.Time  `json:"updatedAt"` // 文字或其它字段最后修改的时间
+}
+
+// hasMetadata 判断除文字、ID、来源和时间戳外是否还有其它字段，新增字段无需修改此处
+func (t Todo) hasMetadata() bool {
+	return !reflect.DeepEqual(t, Todo{ID: t.ID, Text: t.Text, Source: t.Source, CreatedAt: t.CreatedAt, UpdatedAt: t.UpdatedAt})
+}
+
+// 待办的创建途径
+const (
+	sourceUI       = "ui"       // 主窗口输入框
+	sourceQuickAdd = "quickadd" // 托盘快速添加
+	sourceImport   = "import"   // 从链接导入
+	sourceWelcome  = "welcome"  // 首次启动添加的示例
+	sourceControl  = "control"  // 本机控制接口
+)
+
+// sourceLabels 创建途径的显示名称，按视图下拉框中的顺序排列
+var sourceLabels = []struct{ source, label string }{
+	{sourceUI, "输入框"},
+	{sourceQuickAdd, "快速添加"},
+	{sourceImport, "导入"},
+	{sourceWelcome, "示例"},
+	{sourceControl, "控制接口"},
+}
+
+// sourceLabel 返回创建途径的显示名称，未知的途径原样显示
+func sourceLabel(source string) string {
+	for _, s := range sourceLabels {
+		if s.source == source {
+			return s.label
+		}
+	}
+	return source
+}
+
+// newID 生成随机的 UUID（第 4 版）
+func newID() string {
+	var b [16]byte
+	_, _ = rand.Read(b[:])
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+}
+
+// fillIDs 为没有 ID 的旧数据生成 ID；txt 格式不保存 ID，每次加载都会重新生成
+func fillIDs(todos []Todo) {
+	for i := range todos {
+		if todos[i].ID == "" {
+			todos[i].ID = newID()
+		}
+	}
+}
+
+// blockedBy 返回 t 所依赖且尚未完成的待办；依赖已完成或已删除时返回 false
+func blockedBy(t Todo, todos []Todo) (Todo, bool) {
+	if t.DependsOn == "" {
+		return Todo{}, false
+	}
+	for _, d := range todos {
+		if d.ID == t.DependsOn {
+			return d, !d.Done
+		}
+	}
+	return Todo{}, false
+}
+
+// dependsOnChain 判断 t 是否直接或间接依赖 id，用于避免循环依赖；
+// 经过已完成的待办也继续查找，取消完成后循环仍然存在
+func dependsOnChain(t Todo, id string, todos []Todo) bool {
+	seen := map[string]bool{}
+	for t.DependsOn != "" && !seen[t.ID] {
+		if t.DependsOn == id {
+			return true
+		}
+		seen[t.ID] = true
+		i := slices.IndexFunc(todos, func(d Todo) bool { return d.ID == t.DependsOn })
+		if i < 0 {
+			return false
+		}
+		t = todos[i]
+	}
+	return false
+}
+
+// fillTimestamps 为旧数据补全缺失的时间戳，fallback 通常为数据文件的修改时间
+func fillTimestamps(todos []Todo, fallback time.Time) {
+	for i := range todos {
+		if todos[i].CreatedAt.IsZero() {
+			todos[i].CreatedAt = fallback
+		}
+		if todos[i].UpdatedAt.IsZero() {
+			todos[i].UpdatedAt = todos[i].CreatedAt
+		}
+	}
+}
+
+// deferred 是否还没到开始日期
+func (t Todo) deferred(now time.Time) bool {
+	return t.StartAt != nil && now.Before(*t.StartAt)
+}
+
+// detailText 详情对话框中显示的内容
+func (t Todo) detailText() string {
+	const layout = "2006-01-02 15:04"
+	lines := []string{t.Text, "", "创建于 " + t.CreatedAt.Local().Format(layout)}
+	if !t.UpdatedAt.Equal(t.CreatedAt) {
+		lines = append(lines, "修改于 "+t.UpdatedAt.Local().Format(layout))
+	}
+	if meta := t.metaText(time.Now()); meta != "" {
+		lines = append(lines, meta)
+	}
+	if t.StartAt != nil {
+		lines = append(lines, "开始于 "+t.StartAt.Local().Format("2006-01-02"))
+	}
+	if t.RemindBefore != nil {
+		lines = append(lines, "提醒 "+remindLabel(*t.RemindBefore))
+	}
+	if t.Outcome != "" {
+		lines = append(lines, "结果 "+t.Outcome)
+	}
+	if t.Sound != "" {
+		lines = append(lines, "提醒声音 "+soundLabel(t.Sound))
+	}
+	if t.HideInTray {
+		lines = append(lines, "不在托盘显示")
+	}
+	if t.Attachment != "" {
+		lines = append(lines, "附件 "+t.Attachment)
+	}
+	if t.Source != "" {
+		lines = append(lines, "来源 "+sourceLabel(t.Source))
+	}
+	if t.CompletedAt != nil {
+		lines = append(lines, "完成于 "+t.CompletedAt.Local().Format(layout))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// 待办状态。完成与否由 Done 记录，与状态无关
+const (
+	StatusActive  = ""
+	StatusWaiting = "waiting" // 等待他人或被阻塞，暂时无法推进
+)
+
+// viewOptions 视图下拉框的选项，第一个为不筛选；最后是按创建途径筛选的选项
+var viewOptions = append([]string{"全部", "未完成", "今天", "可执行", "等待中", "逾期", "未来", "已完成", "有附件"}, sourceViews()...)
+
+// sourceViewPrefix 按创建途径筛选的视图名前缀
+const sourceViewPrefix = "来源："
+
+func sourceViews() []string {
+	var views []string
+	for _, s := range sourceLabels {
+		views = append(views, sourceViewPrefix+s.label)
+	}
+	return views
+}
+
+// viewMatch 判断待办是否属于所选视图；"今天" 包含今天到期与已逾期的待办。
+// 尚未到开始日期的待办只出现在"未来"视图中，已完成的只出现在"全部"与"已完成"中
+func viewMatch(option string, t Todo, now time.Time) bool {
+	if option == "已完成" || t.Done {
+		return t.Done && (option == "已完成" || option == "全部")
+	}
+	if option == "未来" || t.deferred(now) {
+		return option == "未来" && t.deferred(now)
+	}
+	switch option {
+	case "未完成":
+		return true
+	case "今天":
+		return t.Due != nil && daysBetween(now, t.Due.In(time.Local)) <= 0
+	case "可执行":
+		return t.Status != StatusWaiting
+	case "等待中":
+		return t.Status == StatusWaiting
+	case "逾期":
+		return t.overdue(now)
+	case "有附件":
+		return t.Attachment != ""
+	}
+	if label, ok := strings.CutPrefix(option, sourceViewPrefix); ok {
+		return t.Source != "" && sourceLabel(t.Source) == label
+	}
+	return true
+}
+
+// Priority 优先级，零值表示未设置
+type Priority int
+
+const (
+	PriorityNone Priority = iota
+	PriorityLow
+	PriorityMedium
+	PriorityHigh
+)
+
+func (p Priority) String() string {
+	switch p {
+	case PriorityLow:
+		return "低"
+	case PriorityMedium:
+		return "中"
+	case PriorityHigh:
+		return "高"
+	}
+	return ""
+}
+
+// newTodo 由输入内容创建待办：按偏好识别行内标记，再整理并校验标题
+func newTodo(p fyne.Preferences, text string, now time.Time) (Todo, error) {
+	t := Todo{Text: text}
+	if p.Bool(prefSmartTokens) {
+		t = parseTokens(text, now)
+		if strings.TrimSpace(t.Text) == "" && strings.TrimSpace(text) != "" {
+			return Todo{}, errOnlyTokens
+		}
+	}
+	title, err := checkTodoText(p, t.Text)
+	if err != nil {
+		return Todo{}, err
+	}
+	t.Text = title
+	if p.Bool(prefNormalizeTags) {
+		t.Tags = normalizeTags(t.Tags)
+	}
+	t.ID = newID()
+	t.CreatedAt, t.UpdatedAt = now, now
+	return t, nil
+}
+
+// overdue 判断是否已过截止时间
+func (t Todo) overdue(now time.Time) bool {
+	return !t.Done && t.Due != nil && now.After(*t.Due)
+}
+
+// shiftDue 把截止时间移到 day 这一天并保留原来的时刻；移动后仍早于 now 时取当天 23:59
+func shiftDue(due, day, now time.Time) time.Time {
+	due, day = due.In(time.Local), day.In(time.Local)
+	moved := time.Date(day.Year(), day.Month(), day.Day(), due.Hour(), due.Minute(), 0, 0, time.Local)
+	if moved.Before(now) {
+		moved = time.Date(day.Year(), day.Month(), day.Day(), 23, 59, 0, 0, time.Local)
+	}
+	return moved.UTC()
+}
+
+// nextWeekday 返回 now 之后最近的星期 wd 的零点（本地时间）；今天正是 wd 时，
+// includeToday 为真返回今天，否则返回下周的这一天
+func nextWeekday(now time.Time, wd time.Weekday, includeToday bool) time.Time {
+	days := (int(wd) - int(now.Weekday()) + 7) % 7
+	if days == 0 && !includeToday {
+		days = 7
+	}
+	return startOfDay(now).AddDate(0, 0, days)
+}
+
+// metaText 返回列表行中显示的优先级、截止日期与标签，没有时为空
+func (t Todo) metaText(now time.Time) string {
+	var parts []string
+	if t.Priority != PriorityNone {
+		parts = append(parts, "!"+t.Priority.String())
+	}
+	if t.Due != nil {
+		parts = append(parts, "📅 "+formatDue(*t.Due, now))
+	}
+	if t.Context != "" {
+		parts = append(parts, t.Context)
+	}
+	for _, tag := range t.Tags {
+		parts = append(parts, "#"+tag)
+	}
+	return strings.Join(parts, "  ")
+}
+
+// 行首序号的编号方式
+const (
+	rowNumbersOff     = ""        // 不显示
+	rowNumbersShown   = "display" // 按当前筛选与排序后的显示顺序
+	rowNumbersStorage = "stored"  // 按数据文件中的顺序，筛选时会有间隔
+)
+
+var rowNumberModes = []struct{ mode, label string }{
+	{rowNumbersOff, "不显示"},
+	{rowNumbersShown, "按显示顺序"},
+	{rowNumbersStorage, "按保存顺序"},
+}
+
+// 列表行显示附加信息的详细程度
+const (
+	densityMinimal = "minimal" // 只显示文字
+	densityNormal  = "normal"  // 优先级、截止时间、情境与标签
+	densityFull    = "full"    // 另外显示开始、提醒、创建与完成时间
+)
+
+var densities = []struct{ density, label string }{
+	{densityMinimal, "精简：只显示文字"},
+	{densityNormal, "标准：优先级、截止时间与标签"},
+	{densityFull, "完整：全部信息"},
+}
+
+// rowMetaText 列表行中文字下方的附加信息，按详细程度取舍
+func (t Todo) rowMetaText(density string, now time.Time) string {
+	if density == densityMinimal {
+		return ""
+	}
+	meta := t.metaText(now)
+	if density != densityFull {
+		return meta
+	}
+	parts := []string{meta}
+	if t.StartAt != nil {
+		parts = append(parts, "开始 "+t.StartAt.Local().Format("01-02"))
+	}
+	if t.RemindBefore != nil {
+		parts = append(parts, "🔔 "+remindLabel(*t.RemindBefore))
+	}
+	parts = append(parts, "创建 "+formatDue(t.CreatedAt, now))
+	if t.CompletedAt != nil {
+		parts = append(parts, "完成 "+formatDue(*t.CompletedAt, now))
+	}
+	return strings.TrimSpace(strings.Join(parts, "  "))
+}
+
+// formatDue 以本地时间显示截止时间，近期日期用今天/明天表示，23:59 视为全天不显示时刻
+func formatDue(due, now time.Time) string {
+	due, now = due.In(time.Local), now.In(time.Local)
+	var day string
+	switch days := daysBetween(now, due); {
+	case days == 0:
+		day = "今天"
+	case days == 1:
+		day = "明天"
+	case days == -1:
+		day = "昨天"
+	case due.Year() == now.Year():
+		day = due.Format("01-02")
+	default:
+		day = due.Format("2006-01-02")
+	}
+	if due.Hour() == 23 && due.Minute() == 59 {
+		return day
+	}
+	return day + " " + due.Format("15:04")
+}
+
+// daysBetween 返回两个本地日期相差的天数，按日历日计算以避开夏令时的 23/25 小时
+func daysBetween(from, to time.Time) int {
+	y1, m1, d1 := from.Date()
+	y2, m2, d2 := to.Date()
+	a := time.Date(y1, m1, d1, 0, 0, 0, 0, time.UTC)
+	b := time.Date(y2, m2, d2, 0, 0, 0, 0, time.UTC)
+	return int(b.Sub(a).Hours() / 24)
+}
@@ -0,0 +1,39 @@
+package main
+
+import "testing"
+
+func TestDependsOnChain(t *testing.T) {
+	todos := []Todo{
+		{ID: "a", DependsOn: "b"},
+		{ID: "b", DependsOn: "c", Done: true},
+		{ID: "c"},
+		{ID: "d", DependsOn: "missing"},
+		{ID: "e", DependsOn: "f"},
+		{ID: "f", DependsOn: "e"},
+	}
+	byID := func(id string) Todo {
+		for _, t := range todos {
+			if t.ID == id {
+				return t
+			}
+		}
+		return Todo{ID: id}
+	}
+	tests := []struct {
+		from, id string
+		want     bool
+	}{
+		{"a", "b", true},  // 直接依赖
+		{"a", "c", true},  // 经过已完成的 b 间接依赖
+		{"c", "a", false}, // 没有依赖
+		{"b", "a", false}, // 依赖方向相反
+		{"d", "a", false}, // 依赖的条目不存在
+		{"e", "a", false}, // 已有的循环不会死循环
+		{"e", "e", true},
+	}
+	for _, tt := range tests {
+		if got := dependsOnChain(byID(tt.from), tt.id, todos); got != tt.want {
+			t.Errorf("dependsOnChain(%s, %s) = %v, want %v", tt.from, tt.id, got, tt.want)
+		}
+	}
+}
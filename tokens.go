@@ -0,0 +1,122 @@
+package main
+
+import (
+	"regexp"
+	"strings"
+	"time"
+)
+
+// 行内标记语法（设置中开启"智能识别"后生效），标记之间以空格分隔：
+//
+//	!高 !中 !低（或 !high !med !low、!h !m !l）  设置优先级
+//	@今天 @明天 @后天 @2026-01-02 @01-02          设置截止日期（当天 23:59）
+//	@18:00 @明天18:00 @01-02T18:00                同时指定时间
+//	#工作                                         添加标签
+//
+// 无法识别的标记原样保留在标题中。
+var priorityTokens = map[string]Priority{
+	"高": PriorityHigh, "high": PriorityHigh, "h": PriorityHigh,
+	"中": PriorityMedium, "medium": PriorityMedium, "med": PriorityMedium, "m": PriorityMedium,
+	"低": PriorityLow, "low": PriorityLow, "l": PriorityLow,
+}
+
+// parseTokens 从输入中提取优先级、截止日期与标签，返回去掉标记后的待办。
+// 逐行识别并保留换行；只由标记组成的行去掉后不留空行
+func parseTokens(text string, now time.Time) Todo {
+	var t Todo
+	var lines []string
+	for _, line := range strings.Split(text, "\n") {
+		fields := strings.Fields(line)
+		var words []string
+		for _, w := range fields {
+			switch {
+			case len(w) > 1 && w[0] == '!':
+				if p, ok := priorityTokens[strings.ToLower(w[1:])]; ok {
+					t.Priority = p
+					continue
+				}
+			case len(w) > 1 && w[0] == '@':
+				if due, ok := parseDue(w[1:], now); ok {
+					utc := due.UTC()
+					t.Due = &utc
+					continue
+				}
+			case len(w) > 1 && w[0] == '#':
+				t.Tags = appendTag(t.Tags, w[1:])
+				continue
+			}
+			words = append(words, w)
+		}
+		if len(words) > 0 || len(fields) == 0 {
+			lines = append(lines, strings.Join(words, " "))
+		}
+	}
+	t.Text = strings.Join(lines, "\n")
+	return t
+}
+
+// appendTag 追加标签并去重
+func appendTag(tags []string, tag string) []string {
+	for _, t := range tags {
+		if t == tag {
+			return tags
+		}
+	}
+	return append(tags, tag)
+}
+
+// dueToken 把日期标记拆成日期与可选的时间两部分
+var dueToken = regexp.MustCompile(`^(.*?)T?(\d{1,2}:\d{2})?$`)
+
+// parseDue 解析日期标记，结果为本地时间；只给日期时取当天 23:59
+func parseDue(s string, now time.Time) (time.Time, bool) {
+	now = now.In(time.Local)
+	m := dueToken.FindStringSubmatch(s)
+	if m == nil || s == "" {
+		return time.Time{}, false
+	}
+	datePart, clock := m[1], m[2]
+	if datePart == "" && clock == "" {
+		return time.Time{}, false
+	}
+
+	var day time.Time
+	switch strings.ToLower(datePart) {
+	case "", "今天", "today":
+		day = now
+	case "明天", "tomorrow":
+		day = now.AddDate(0, 0, 1)
+	case "后天":
+		day = now.AddDate(0, 0, 2)
+	default:
+		if d, err := time.ParseInLocation("2006-01-02", datePart, time.Local); err == nil {
+			day = d
+		} else if d, err := time.ParseInLocation("01-02", datePart, time.Local); err == nil {
+			// 只写月日时取今天之后最近的一天；02-29 取下一个闰年，
+			// time.Date 在平年会把它顺延成 3 月 1 日，因此检查月份没有变化
+			for y := now.Year(); ; y++ {
+				day = time.Date(y, d.Month(), d.Day(), 0, 0, 0, 0, time.Local)
+				if day.Month() == d.Month() && !day.Before(startOfDay(now)) {
+					break
+				}
+			}
+		} else {
+			return time.Time{}, false
+		}
+	}
+
+	hour, minute := 23, 59
+	if clock != "" {
+		c, err := time.Parse("15:04", clock)
+		if err != nil {
+			return time.Time{}, false
+		}
+		hour, minute = c.Hour(), c.Minute()
+	}
+	return time.Date(day.Year(), day.Month(), day.Day(), hour, minute, 0, 0, time.Local), true
+}
+
+func startOfDay(t time.Time) time.Time {
+	y, m, d := t.Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, t.Location())
+}
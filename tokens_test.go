@@ -0,0 +1,46 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseDueMonthDay(t *testing.T) {
+	for _, c := range []struct {
+		now, token, want string
+	}{
+		{"2026-01-10", "03-01", "2026-03-01"},
+		{"2026-03-02", "03-01", "2027-03-01"},
+		{"2026-01-10", "02-29", "2028-02-29"},
+		{"2027-03-01", "02-29", "2028-02-29"},
+		{"2028-02-29", "02-29", "2028-02-29"},
+		{"2028-03-01", "02-29", "2032-02-29"},
+	} {
+		now, _ := time.ParseInLocation("2006-01-02", c.now, time.Local)
+		got, ok := parseDue(c.token, now.Add(9*time.Hour))
+		if !ok {
+			t.Errorf("parseDue(%q) at %s failed", c.token, c.now)
+			continue
+		}
+		if day := got.Format("2006-01-02"); day != c.want {
+			t.Errorf("parseDue(%q) at %s = %s, want %s", c.token, c.now, day, c.want)
+		}
+	}
+}
+
+func TestParseTokensKeepsLines(t *testing.T) {
+	now := time.Date(2026, 1, 10, 9, 0, 0, 0, time.Local)
+	got := parseTokens("准备发布 !高\n  更新文档  #工作\n@2026-01-12\n\n通知大家", now)
+	if want := "准备发布\n更新文档\n\n通知大家"; got.Text != want {
+		t.Errorf("text = %q, want %q", got.Text, want)
+	}
+	if got.Priority != PriorityHigh {
+		t.Errorf("priority = %v, want high", got.Priority)
+	}
+	if len(got.Tags) != 1 || got.Tags[0] != "工作" {
+		t.Errorf("tags = %v, want [工作]", got.Tags)
+	}
+	if got.Due == nil || got.Due.In(time.Local).Format("2006-01-02") != "2026-01-12" {
+		t.Errorf("due = %v, want 2026-01-12", got.Due)
+	}
+}
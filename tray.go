@@ -0,0 +1,84 @@
+package main
+
+import (
+	"log/slog"
+	"time"
+
+	"fyne.io/fyne/v2"
+)
+
+// 托盘菜单可选快捷操作的标识
+const (
+	trayQuickAdd = "quickAdd"
+	trayFocus    = "focus"
+	traySettings = "settings"
+	trayNotes    = "notes"
+	trayDone     = "done"
+	trayStats    = "stats"
+	trayTheme    = "theme"
+)
+
+// trayAction 托盘菜单中可按需显示的快捷操作，"打开" 与 "退出" 始终显示
+type trayAction struct {
+	ID    string
+	Label string
+}
+
+// trayActions 按菜单中的显示顺序列出所有可选操作
+var trayActions = []trayAction{
+	{trayQuickAdd, "快速添加"},
+	{trayDone, "查看已完成"},
+	{trayStats, "统计"},
+	{trayFocus, "专注模式"},
+	{trayNotes, "便签"},
+	{traySettings, "设置"},
+	{trayTheme, "切换主题"},
+}
+
+// defaultTrayActions 未设置时显示的快捷操作
+var defaultTrayActions = []string{trayQuickAdd, traySettings}
+
+// enabledTrayActions 返回偏好设置中启用的快捷操作，保持 trayActions 的顺序
+func enabledTrayActions(p fyne.Preferences) []trayAction {
+	on := map[string]bool{}
+	for _, id := range p.StringListWithFallback(prefTrayActions, defaultTrayActions) {
+		on[id] = true
+	}
+	var acts []trayAction
+	for _, act := range trayActions {
+		if on[act.ID] {
+			acts = append(acts, act)
+		}
+	}
+	return acts
+}
+
+// trayListMax 托盘"待办"子菜单最多列出的条数
+const trayListMax = 10
+
+// maxTrayDelay 托盘启动延迟的上限，单位秒
+const maxTrayDelay = 60
+
+// trayRetryDelays 首次设置托盘后再次设置的间隔。开机自启时面板可能尚未就绪，
+// 而 Fyne 不报告托盘是否设置成功，因此按递增的间隔重新设置几次
+var trayRetryDelays = []time.Duration{2 * time.Second, 5 * time.Second, 15 * time.Second, 30 * time.Second}
+
+// setupTray 等待 delay 后在主线程调用 apply 设置托盘图标与菜单，之后按 trayRetryDelays 重试
+func setupTray(delay time.Duration, apply func()) {
+	if delay <= 0 {
+		apply()
+		slog.Debug("tray set up")
+	}
+	go func() {
+		if delay > 0 {
+			time.Sleep(delay)
+			fyne.Do(apply)
+			slog.Debug("tray set up", "delay", delay)
+		}
+		for i, d := range trayRetryDelays {
+			time.Sleep(d)
+			fyne.Do(apply)
+			slog.Debug("tray re-applied", "attempt", i+1)
+		}
+	}()
+}
@@ -0,0 +1,34 @@
+package main
+
+import (
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+)
+
+const welcomeText = `待办事项常驻在系统托盘，关闭窗口只是隐藏，点击托盘图标的"打开待办事项"即可找回。
+
+· 在底部输入框输入内容，回车添加
+· 勾选左侧复选框即完成并移除
+· 右侧按钮可以复制、设置颜色，"⋯"里有更多操作`
+
+// exampleTodos 首次运行时可选添加的示例，文字前标明"示例"便于识别和删除
+var exampleTodos = []string{
+	"示例：勾选左侧复选框完成这一条",
+	"示例：双击文字可以编辑",
+	"示例：关闭窗口后从托盘图标重新打开",
+}
+
+// showWelcome 首次运行时的简短说明，选择是否添加示例；无论如何关闭都不再出现
+func showWelcome(win fyne.Window, onSeed func(), onDone func()) {
+	text := widget.NewLabel(welcomeText)
+	text.Wrapping = fyne.TextWrapWord
+	d := dialog.NewCustomConfirm("欢迎使用", "添加示例", "开始使用", text, func(seed bool) {
+		if seed {
+			onSeed()
+		}
+		onDone()
+	}, win)
+	d.Resize(fyne.NewSize(360, 0))
+	d.Show()
+}
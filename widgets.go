@@ -0,0 +1,333 @@
+package main
+
+import (
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/driver/desktop"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+// escEntry 单行输入框，按 Esc 或失去焦点时回调
+type escEntry struct {
+	widget.Entry
+	onEscape    func()
+	onFocusLost func()
+}
+
+func newEscEntry() *escEntry {
+	e := &escEntry{}
+	e.ExtendBaseWidget(e)
+	return e
+}
+
+func (e *escEntry) TypedKey(key *fyne.KeyEvent) {
+	if key.Name == fyne.KeyEscape && e.onEscape != nil {
+		e.onEscape()
+		return
+	}
+	e.Entry.TypedKey(key)
+}
+
+func (e *escEntry) FocusLost() {
+	e.Entry.FocusLost()
+	if e.onFocusLost != nil {
+		e.onFocusLost()
+	}
+}
+
+// softEntry 主输入框：回车始终提交；开启软换行后变为多行，Shift+回车插入换行。
+// 撰写模式下为多行输入框，回车换行，Shift+回车提交。
+// onKey 先于默认处理收到按键，返回 true 表示已处理
+type softEntry struct {
+	widget.Entry
+	shift    bool
+	composer bool
+	onKey    func(key *fyne.KeyEvent) bool
+}
+
+func newSoftEntry() *softEntry {
+	e := &softEntry{}
+	e.ExtendBaseWidget(e)
+	return e
+}
+
+// setSoftBreak 切换是否允许 Shift+回车换行
+func (e *softEntry) setSoftBreak(on bool) {
+	e.MultiLine = on
+	e.Wrapping = fyne.TextWrapOff
+	if on {
+		e.Wrapping = fyne.TextWrapWord
+		e.SetMinRowsVisible(1)
+	}
+	e.Refresh()
+}
+
+// setComposer 切换撰写模式，lines 为显示的行数，0 表示关闭
+func (e *softEntry) setComposer(lines int) {
+	e.composer = lines > 0
+	if !e.composer {
+		return
+	}
+	e.MultiLine = true
+	e.Wrapping = fyne.TextWrapWord
+	e.SetMinRowsVisible(lines)
+	e.Refresh()
+}
+
+func (e *softEntry) KeyDown(key *fyne.KeyEvent) {
+	if key.Name == desktop.KeyShiftLeft || key.Name == desktop.KeyShiftRight {
+		e.shift = true
+	}
+	e.Entry.KeyDown(key)
+}
+
+func (e *softEntry) KeyUp(key *fyne.KeyEvent) {
+	if key.Name == desktop.KeyShiftLeft || key.Name == desktop.KeyShiftRight {
+		e.shift = false
+	}
+	e.Entry.KeyUp(key)
+}
+
+// TypedKey 多行模式下 Entry 默认回车换行、Shift+回车提交，这里反过来
+func (e *softEntry) TypedKey(key *fyne.KeyEvent) {
+	if e.onKey != nil && e.onKey(key) {
+		return
+	}
+	if e.composer || !e.MultiLine || (key.Name != fyne.KeyReturn && key.Name != fyne.KeyEnter) {
+		e.Entry.TypedKey(key)
+		return
+	}
+	if !e.shift {
+		if e.OnSubmitted != nil {
+			e.OnSubmitted(e.Text)
+		}
+		return
+	}
+	// 暂时去掉提交回调，让 Entry 按普通多行输入插入换行
+	submit := e.OnSubmitted
+	e.OnSubmitted = nil
+	e.Entry.TypedKey(key)
+	e.OnSubmitted = submit
+}
+
+// tapLabel 支持单击、双击回调的标签，不可选中文字，因此双击不会与选择冲突。
+// maxLines 大于 0 时最多显示该行数，超出部分以省略号结尾，单击展开或收起
+type tapLabel struct {
+	widget.Label
+	onTap       func()
+	onDoubleTap func()
+
+	full     string
+	maxLines int
+	expanded bool
+	width    float32
+}
+
+func newTapLabel(text string, maxLines int) *tapLabel {
+	l := &tapLabel{full: text, maxLines: maxLines}
+	l.Text = text
+	l.Wrapping = fyne.TextWrapWord
+	if maxLines > 0 {
+		// 按字符换行，与 clampText 的估算方式一致
+		l.Wrapping = fyne.TextWrapBreak
+	}
+	l.ExtendBaseWidget(l)
+	return l
+}
+
+func (l *tapLabel) Resize(size fyne.Size) {
+	l.Label.Resize(size)
+	if l.maxLines <= 0 || size.Width == l.width {
+		return
+	}
+	l.width = size.Width
+	l.clamp()
+}
+
+// clamp 按当前宽度重新计算显示的文字
+func (l *tapLabel) clamp() {
+	text := l.full
+	if !l.expanded {
+		th := l.Theme()
+		style := l.TextStyle
+		sizeName := l.SizeName
+		if sizeName == "" {
+			sizeName = theme.SizeNameText
+		}
+		textSize := th.Size(sizeName)
+		avail := l.width - 2*th.Size(theme.SizeNameInnerPadding)
+		text = clampText(l.full, l.maxLines, avail, func(s string) float32 {
+			return fyne.MeasureText(s, textSize, style).Width
+		})
+	}
+	if text != l.Text {
+		l.Text = text
+		l.Refresh()
+	}
+}
+
+func (l *tapLabel) Tapped(*fyne.PointEvent) {
+	if l.onTap != nil {
+		l.onTap()
+	}
+	if l.maxLines <= 0 || (l.Text == l.full && !l.expanded) {
+		return
+	}
+	l.expanded = !l.expanded
+	l.clamp()
+}
+
+func (l *tapLabel) DoubleTapped(*fyne.PointEvent) {
+	if l.onDoubleTap != nil {
+		l.onDoubleTap()
+	}
+}
+
+// 高优先级待办的文字样式
+const (
+	emphasisNone  = ""
+	emphasisBold  = "bold"
+	emphasisLarge = "large" // 加粗并使用副标题字号
+)
+
+// emphasize 按样式加粗或放大标签文字
+func emphasize(l *widget.Label, style string) {
+	switch style {
+	case emphasisBold:
+		l.TextStyle.Bold = true
+	case emphasisLarge:
+		l.TextStyle.Bold = true
+		l.SizeName = theme.SizeNameSubHeadingText
+	}
+}
+
+// swipeMin 水平拖动超过此距离才算滑动
+const swipeMin = 80
+
+// swipeRow 可左右滑动的行：拖动时内容跟随移动，松开时水平距离足够则回调 onSwipe，
+// dir 为 -1（向左）或 1（向右）
+type swipeRow struct {
+	widget.BaseWidget
+	content fyne.CanvasObject
+	offset  float32
+	onSwipe func(dir int)
+}
+
+func newSwipeRow(content fyne.CanvasObject, onSwipe func(dir int)) *swipeRow {
+	r := &swipeRow{content: content, onSwipe: onSwipe}
+	r.ExtendBaseWidget(r)
+	return r
+}
+
+func (r *swipeRow) CreateRenderer() fyne.WidgetRenderer {
+	return &swipeRenderer{row: r}
+}
+
+func (r *swipeRow) Dragged(e *fyne.DragEvent) {
+	r.offset += e.Dragged.DX
+	r.Refresh()
+}
+
+func (r *swipeRow) DragEnd() {
+	dir := 0
+	if r.offset <= -swipeMin {
+		dir = -1
+	} else if r.offset >= swipeMin {
+		dir = 1
+	}
+	r.offset = 0
+	r.Refresh()
+	if dir != 0 && r.onSwipe != nil {
+		r.onSwipe(dir)
+	}
+}
+
+type swipeRenderer struct {
+	row *swipeRow
+}
+
+func (s *swipeRenderer) Layout(size fyne.Size) {
+	s.row.content.Resize(size)
+	s.row.content.Move(fyne.NewPos(s.row.offset, 0))
+}
+
+func (s *swipeRenderer) MinSize() fyne.Size { return s.row.content.MinSize() }
+
+func (s *swipeRenderer) Refresh() {
+	s.Layout(s.row.Size())
+	canvas.Refresh(s.row.content)
+}
+
+func (s *swipeRenderer) Objects() []fyne.CanvasObject { return []fyne.CanvasObject{s.row.content} }
+
+func (s *swipeRenderer) Destroy() {}
+
+// 行间分隔线样式
+const (
+	separatorLine   = ""       // 实线，与 widget.NewSeparator 相同
+	separatorDotted = "dotted" // 浅色点线
+	separatorNone   = "none"   // 不画线，只靠间距区分
+)
+
+// rowSeparator 按样式生成行间分隔线，不需要分隔线时返回 nil
+func rowSeparator(style string) fyne.CanvasObject {
+	switch style {
+	case separatorNone:
+		return nil
+	case separatorDotted:
+		return newDottedLine()
+	}
+	return widget.NewSeparator()
+}
+
+// dottedLine 横向点线，点数随宽度变化
+type dottedLine struct {
+	widget.BaseWidget
+}
+
+func newDottedLine() *dottedLine {
+	l := &dottedLine{}
+	l.ExtendBaseWidget(l)
+	return l
+}
+
+func (l *dottedLine) CreateRenderer() fyne.WidgetRenderer {
+	return &dottedRenderer{line: l}
+}
+
+type dottedRenderer struct {
+	line *dottedLine
+	dots []fyne.CanvasObject
+}
+
+// dotSize 点的边长，dotGap 相邻两点的间隔
+const dotSize, dotGap = 2, 4
+
+func (r *dottedRenderer) Layout(size fyne.Size) {
+	n := int(size.Width / (dotSize + dotGap))
+	fill := r.line.Theme().Color(theme.ColorNameSeparator, fyne.CurrentApp().Settings().ThemeVariant())
+	for len(r.dots) < n {
+		r.dots = append(r.dots, canvas.NewRectangle(fill))
+	}
+	r.dots = r.dots[:n]
+	y := (size.Height - dotSize) / 2
+	for i, d := range r.dots {
+		d.(*canvas.Rectangle).FillColor = fill
+		d.Resize(fyne.NewSquareSize(dotSize))
+		d.Move(fyne.NewPos(float32(i)*(dotSize+dotGap), y))
+	}
+}
+
+func (r *dottedRenderer) MinSize() fyne.Size {
+	return fyne.NewSize(0, r.line.Theme().Size(theme.SizeNameSeparatorThickness)+dotSize)
+}
+
+func (r *dottedRenderer) Refresh() {
+	r.Layout(r.line.Size())
+	canvas.Refresh(r.line)
+}
+
+func (r *dottedRenderer) Objects() []fyne.CanvasObject { return r.dots }
+
+func (r *dottedRenderer) Destroy() {}
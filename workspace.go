@@ -0,0 +1,46 @@
+package main
+
+import (
+	"log/slog"
+	"os"
+	"os/exec"
+	"runtime"
+	"sync"
+	"time"
+)
+
+// wmctrlDelay 窗口显示后等待窗口管理器完成映射再设置，过早调用时找不到窗口
+const wmctrlDelay = 300 * time.Millisecond
+
+var wmctrlWarn sync.Once
+
+// setSticky 让标题为 title 的窗口显示在所有工作区
+func setSticky(title string, on bool) {
+	setWindowState(title, "sticky", on)
+}
+
+// setWindowState 开关窗口管理器的窗口状态，如 sticky（所有工作区）、above（置顶）。
+// Fyne 没有对应接口，这里借助 X11 下的 wmctrl；其它平台、Wayland 或未安装 wmctrl 时只记录一次警告
+func setWindowState(title, state string, on bool) {
+	if runtime.GOOS != "linux" || os.Getenv("WAYLAND_DISPLAY") != "" && os.Getenv("DISPLAY") == "" {
+		wmctrlWarn.Do(func() { slog.Warn("window state is only supported on X11", "state", state) })
+		return
+	}
+	path, err := exec.LookPath("wmctrl")
+	if err != nil {
+		wmctrlWarn.Do(func() { slog.Warn("window state needs wmctrl", "state", state, "err", err) })
+		return
+	}
+	action := "remove," + state
+	if on {
+		action = "add," + state
+	}
+	go func() {
+		time.Sleep(wmctrlDelay)
+		if out, err := exec.Command(path, "-r", title, "-b", action).CombinedOutput(); err != nil {
+			slog.Warn("wmctrl failed", "err", err, "output", string(out))
+			return
+		}
+		slog.Debug("window state", "title", title, "state", state, "on", on)
+	}()
+}